@@ -4,16 +4,17 @@ import (
 	"fmt"
 
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
-	"github.com/adavidalbertson/cryptopals/xor"
 )
 
 func getAndValidateBlockSize(input, key, iv []byte) (blockSize int, err error) {
-	blockSize = len(key)
-	if blockSize != 16 && blockSize != 24 && blockSize != 32 {
-		err = fmt.Errorf("key has length %d, which is not a valid block size for AES", blockSize)
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		err = fmt.Errorf("key has length %d, which is not a valid key size for AES", len(key))
 		return
 	}
 
+	// AES always uses 16-byte blocks, regardless of key size
+	blockSize = 16
+
 	if iv != nil && len(iv) != blockSize {
 		err = fmt.Errorf("Initialization Vector not equal to block length")
 		return
@@ -40,23 +41,15 @@ func Encrypt(plaintext, key, iv []byte) (ciphertext []byte, err error) {
 		iv = make([]byte, blockSize)
 	}
 
-	ciphertext = make([]byte, len(plaintext))
-	curBlock := iv
-	for len(plaintext) > 0 {
-		diff, err := xor.Xor(plaintext[:blockSize], curBlock)
-		if err != nil {
-			return make([]byte, 0), err
-		}
-
-		curBlock, err = ecb.Encrypt(diff, key)
-		plaintext = plaintext[blockSize:]
-		ciphertext = append(ciphertext, curBlock...)
+	block, err := ecb.NewCipher(key)
+	if err != nil {
+		return
 	}
 
-	// per openssl, padding is always added, so strip it off
-	ciphertext = ciphertext[len(ciphertext)/2:]
+	ciphertext = make([]byte, len(plaintext))
+	NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
 
-	return ciphertext, err
+	return
 }
 
 // Decrypt decrypts bytes using AES in the CBC mode of operation.
@@ -72,25 +65,13 @@ func Decrypt(ciphertext, key, iv []byte) (plaintext []byte, err error) {
 		iv = make([]byte, blockSize)
 	}
 
-	plaintext = make([]byte, len(ciphertext))
-	prevBlock := iv
-	for len(ciphertext) > 0 {
-		curBlock, err := ecb.Decrypt(ciphertext[:blockSize], key)
-		if err != nil {
-			return make([]byte, 0), err
-		}
-
-		curBlock, err = xor.Xor(curBlock, prevBlock)
-		if err != nil {
-			return make([]byte, 0), err
-		}
-
-		prevBlock = ciphertext[:blockSize]
-		ciphertext = ciphertext[blockSize:]
-		plaintext = append(plaintext, curBlock...)
+	block, err := ecb.NewCipher(key)
+	if err != nil {
+		return
 	}
 
-	plaintext = plaintext[len(plaintext)/2:]
+	plaintext = make([]byte, len(ciphertext))
+	NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
 
-	return plaintext, err
+	return
 }
@@ -1,9 +1,9 @@
 package cbc
 
 import (
-	"fmt"
 	"strings"
 
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 	"github.com/adavidalbertson/cryptopals/random"
 )
@@ -14,19 +14,30 @@ import (
 type AesCbcOracle struct {
 	prefix, suffix string
 	iv, key        []byte
+	padder         padding.Padder
+	asciiOnly      bool
 }
 
+var _ oracle.EncryptionOracle = AesCbcOracle{}
+var _ oracle.AdminChecker = AesCbcOracle{}
+
 // NewAesCbcOracle sets a hardcoded prefix and suffix, and a random key and iv.
+// Messages are padded with PKCS#7.
 // Cryptopals Set 2, Challenge 16
 // https://cryptopals.com/sets/2/challenges/16
 func NewAesCbcOracle() AesCbcOracle {
+	return NewAesCbcOracleWithPadder(padding.Pkcs7Padder{})
+}
+
+// NewAesCbcOracleWithPadder is like NewAesCbcOracle, but uses the given padding scheme.
+func NewAesCbcOracleWithPadder(padder padding.Padder) AesCbcOracle {
 	prefix := "comment1=cooking%20MCs;userdata="
 	suffix := ";comment2=%20like%20a%20pound%20of%20bacon"
 
 	key := random.Bytes(16)
 	iv := random.Bytes(16)
 
-	return AesCbcOracle{prefix, suffix, key, iv}
+	return AesCbcOracle{prefix, suffix, iv, key, padder, false}
 }
 
 // Encrypt strips ';' and '=', appends the oracle's prefix and suffix to the
@@ -34,14 +45,14 @@ func NewAesCbcOracle() AesCbcOracle {
 // This is the "first function" called for in Challenge 16.
 // Cryptopals Set 2, Challenge 16
 // https://cryptopals.com/sets/2/challenges/16
-func (oracle AesCbcOracle) Encrypt(plaintext string) (ciphertext []byte, err error) {
-	plaintext = strings.Replace(plaintext, ";", "", -1)
-	plaintext = strings.Replace(plaintext, "=", "", -1)
-	// plaintext = url.QueryEscape(plaintext)
-	fmt.Println(plaintext)
-
-	plaintext = oracle.prefix + plaintext + oracle.suffix
-	plaintextBytes, err := padding.Pkcs7([]byte(plaintext), 16)
+func (oracle AesCbcOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	userdata := string(plaintext)
+	userdata = strings.Replace(userdata, ";", "", -1)
+	userdata = strings.Replace(userdata, "=", "", -1)
+	// userdata = url.QueryEscape(userdata)
+
+	userdata = oracle.prefix + userdata + oracle.suffix
+	plaintextBytes, err := oracle.padder.Pad([]byte(userdata), 16)
 	if err != nil {
 		return
 	}
@@ -61,22 +72,21 @@ func (oracle AesCbcOracle) Decrypt(ciphertext []byte) (isAdmin bool, err error)
 		return
 	}
 
-	plaintextBytes, err = padding.Pkcs7Unpad(plaintextBytes)
+	if oracle.asciiOnly {
+		err = checkAscii(plaintextBytes)
+		if err != nil {
+			return
+		}
+	}
+
+	plaintextBytes, err = oracle.padder.Unpad(plaintextBytes)
 	if err != nil {
 		return
 	}
 
-	pairs := strings.Split(string(plaintextBytes), ";")
-	for _, pair := range pairs {
-		p := strings.Split(pair, "=")
-		if len(p) != 2 {
-			err = fmt.Errorf("Invalid key-value pair: %s", pair)
-			return
-		}
-
+	for _, p := range parseKeyValues(string(plaintextBytes)) {
 		// p[0], _ = url.QueryUnescape(p[0])
 		// p[1], _ = url.QueryUnescape(p[1])
-		fmt.Printf("%s:\t%s\n", p[0], p[1])
 
 		if p[0] == "admin" && p[1] == "true" {
 			isAdmin = true
@@ -86,3 +96,18 @@ func (oracle AesCbcOracle) Decrypt(ciphertext []byte) (isAdmin bool, err error)
 
 	return
 }
+
+// parseKeyValues splits s into ';'-separated key=value pairs. Pieces which
+// aren't a single pair, such as a block scrambled by a bitflip, are skipped,
+// so an admin pair after them is still found.
+func parseKeyValues(s string) (pairs [][2]string) {
+	for _, pair := range strings.Split(s, ";") {
+		p := strings.Split(pair, "=")
+		if len(p) != 2 {
+			continue
+		}
+		pairs = append(pairs, [2]string{p[0], p[1]})
+	}
+
+	return
+}
@@ -1,7 +1,12 @@
 package cbc
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+
+	"github.com/adavidalbertson/cryptopals/padding"
 )
 
 func TestAesCbcOracle_Encrypt(t *testing.T) {
@@ -16,7 +21,7 @@ func TestAesCbcOracle_Encrypt(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			oracle := NewAesCbcOracle()
-			gotCiphertext, err := oracle.Encrypt(tt.plaintext)
+			gotCiphertext, err := oracle.Encrypt([]byte(tt.plaintext))
 
 			gotAdmin, err := oracle.Decrypt(gotCiphertext)
 
@@ -30,3 +35,128 @@ func TestAesCbcOracle_Encrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestPaddingOracle_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		padder padding.Padder
+	}{
+		{"pkcs7", padding.Pkcs7Padder{}},
+		{"ansi_x923", padding.AnsiX923Padder{}},
+		{"iso_10126", padding.Iso10126Padder{}},
+		{"iso_7816", padding.Iso7816Padder{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := NewPaddingOracleWithPadder(tt.padder)
+			ciphertext, iv, err := oracle.Encrypt()
+			if err != nil {
+				t.Errorf("PaddingOracle.Encrypt() error = %v", err)
+				return
+			}
+
+			if valid, err := oracle.Validate(ciphertext, iv); !valid || err != nil {
+				t.Errorf("PaddingOracle.Validate() = %v, %v, want true, <nil>", valid, err)
+			}
+
+			// corrupting the last byte of the second to last block corrupts the padding
+			ciphertext[len(ciphertext)-17] ^= 0xFF
+			if valid, err := oracle.Validate(ciphertext, iv); valid || err != nil {
+				t.Errorf("PaddingOracle.Validate() = %v, %v on corrupted padding, want false, <nil>", valid, err)
+			}
+		})
+	}
+}
+
+func TestNewKeyAsIvOracle(t *testing.T) {
+	oracle := NewKeyAsIvOracle()
+	if !reflect.DeepEqual(oracle.iv, oracle.key) {
+		t.Errorf("NewKeyAsIvOracle() iv = %x, want key %x", oracle.iv, oracle.key)
+	}
+
+	token, _ := oracle.Encrypt([]byte("some data"))
+	if _, err := oracle.Decrypt(token); err != nil {
+		t.Errorf("AesCbcOracle.Decrypt() error = %v", err)
+	}
+
+	highAscii, _ := padding.Pkcs7([]byte("caf\xc3\xa9"), 16)
+	ciphertext, _ := Encrypt(highAscii, oracle.key, oracle.iv)
+	_, err := oracle.Decrypt(ciphertext)
+	if err == nil {
+		t.Errorf("AesCbcOracle.Decrypt() error = nil, want high-ASCII error")
+		return
+	}
+
+	got, ok := PlaintextFromError(err)
+	if !ok || !reflect.DeepEqual(got, highAscii) {
+		t.Errorf("PlaintextFromError() = %q, %v, want %q, true", got, ok, highAscii)
+	}
+}
+
+func TestPlaintextFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   []byte
+		wantOk bool
+	}{
+		{"nil", nil, nil, false},
+		{"other_error", errors.New("Invalid padding"), nil, false},
+		{"wrapped", fmt.Errorf("Oracle error: %v", checkAscii([]byte{'a', 0x80})), []byte{'a', 0x80}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PlaintextFromError(tt.err)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlaintextFromError() = %x, %v, want %x, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestAesCbcOracle_Decrypt(t *testing.T) {
+	tests := []struct {
+		name      string
+		plaintext string
+		wantAdmin bool
+	}{
+		{"not_admin", "userdata=x;admin=false", false},
+		{"admin", "userdata=x;admin=true", true},
+		{"admin_after_garbage", "userdata=x=y;junk;admin=true", true},
+		{"garbage_only", "userdata=x=y;junk", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := NewAesCbcOracle()
+			padded, _ := padding.Pkcs7([]byte(tt.plaintext), 16)
+			ciphertext, _ := Encrypt(padded, oracle.key, oracle.iv)
+
+			gotAdmin, err := oracle.Decrypt(ciphertext)
+			if err != nil {
+				t.Errorf("AesCbcOracle.Decrypt() error = %v", err)
+				return
+			}
+			if gotAdmin != tt.wantAdmin {
+				t.Errorf("AesCbcOracle.Decrypt() = %v, want %v", gotAdmin, tt.wantAdmin)
+			}
+		})
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantPairs [][2]string
+	}{
+		{"clean", "a=1;b=2", [][2]string{{"a", "1"}, {"b", "2"}}},
+		{"malformed", "a=1;junk;b=2=3;c=4", [][2]string{{"a", "1"}, {"c", "4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotPairs := parseKeyValues(tt.s); !reflect.DeepEqual(gotPairs, tt.wantPairs) {
+				t.Errorf("parseKeyValues() = %q, want %q", gotPairs, tt.wantPairs)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package cbc
 
 import (
+	"crypto/cipher"
 	"encoding/base64"
 	"reflect"
 	"testing"
 
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
 	"github.com/adavidalbertson/cryptopals/fileutils"
+	"github.com/adavidalbertson/cryptopals/random"
 )
 
 type args struct {
@@ -42,51 +46,97 @@ func newTestCaseFromFiles(name, inputPath, outputPath string, key, iv []byte, wa
 }
 
 func TestDecrypt(t *testing.T) {
-	tests := []testCase{
-		newTestCaseFromFiles("challenge_10",
-			"../../challenges/set_2/challenge_10/input.txt",
-			"../../challenges/set_2/challenge_10/output_base64.txt",
-			[]byte("YELLOW SUBMARINE"),
-			nil,
-			false,
-		),
-	}
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []testCase{
+			newTestCaseFromFiles("challenge_10",
+				"../../challenges/set_2/challenge_10/input.txt",
+				"../../challenges/set_2/challenge_10/output_base64.txt",
+				[]byte("YELLOW SUBMARINE"),
+				nil,
+				false,
+			),
+		}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotPlaintextBytes, err := Decrypt(tt.args.input, tt.args.keyBytes, tt.args.iv)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotPlaintextBytes, tt.wantOutput) {
-				t.Errorf("Decrypt() = %v, want %v", gotPlaintextBytes, tt.wantOutput)
-			}
-		})
-	}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				gotPlaintextBytes, err := Decrypt(tt.args.input, tt.args.keyBytes, tt.args.iv)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(gotPlaintextBytes, tt.wantOutput) {
+					t.Errorf("Decrypt() = %v, want %v", gotPlaintextBytes, tt.wantOutput)
+				}
+			})
+		}
+	})
 }
 
 func TestEncrypt(t *testing.T) {
-	tests := []testCase{
-		newTestCaseFromFiles("challenge_10_reverse",
-			"../../challenges/set_2/challenge_10/output_base64.txt",
-			"../../challenges/set_2/challenge_10/input.txt",
-			[]byte("YELLOW SUBMARINE"),
-			nil,
-			false,
-		),
-	}
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []testCase{
+			newTestCaseFromFiles("challenge_10_reverse",
+				"../../challenges/set_2/challenge_10/output_base64.txt",
+				"../../challenges/set_2/challenge_10/input.txt",
+				[]byte("YELLOW SUBMARINE"),
+				nil,
+				false,
+			),
+		}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotCiphertextBytes, err := Encrypt(tt.args.input, tt.args.keyBytes, tt.args.iv)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotCiphertextBytes, tt.wantOutput) {
-				t.Errorf("Encrypt() = %v, want %v", gotCiphertextBytes, tt.wantOutput)
-			}
-		})
-	}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				gotCiphertextBytes, err := Encrypt(tt.args.input, tt.args.keyBytes, tt.args.iv)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(gotCiphertextBytes, tt.wantOutput) {
+					t.Errorf("Encrypt() = %v, want %v", gotCiphertextBytes, tt.wantOutput)
+				}
+			})
+		}
+	})
+}
+
+func TestNewCBCEncrypter(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []struct {
+			name      string
+			key       []byte
+			iv        []byte
+			plaintext []byte
+		}{
+			{"aes_128", random.Bytes(16), random.Bytes(16), random.Bytes(64)},
+			{"aes_192", random.Bytes(24), random.Bytes(16), random.Bytes(64)},
+			{"aes_256", random.Bytes(32), random.Bytes(16), random.Bytes(64)},
+			{"empty", random.Bytes(16), random.Bytes(16), []byte{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				block, err := ecb.NewCipher(tt.key)
+				if err != nil {
+					t.Errorf("ecb.NewCipher() error = %v", err)
+					return
+				}
+
+				gotCiphertext := make([]byte, len(tt.plaintext))
+				NewCBCEncrypter(block, tt.iv).CryptBlocks(gotCiphertext, tt.plaintext)
+
+				wantCiphertext := make([]byte, len(tt.plaintext))
+				cipher.NewCBCEncrypter(block, tt.iv).CryptBlocks(wantCiphertext, tt.plaintext)
+
+				if !reflect.DeepEqual(gotCiphertext, wantCiphertext) {
+					t.Errorf("NewCBCEncrypter().CryptBlocks() = %v, want %v", gotCiphertext, wantCiphertext)
+				}
+
+				gotPlaintext := make([]byte, len(gotCiphertext))
+				NewCBCDecrypter(block, tt.iv).CryptBlocks(gotPlaintext, gotCiphertext)
+
+				if !reflect.DeepEqual(gotPlaintext, tt.plaintext) {
+					t.Errorf("NewCBCDecrypter().CryptBlocks() = %v, want %v", gotPlaintext, tt.plaintext)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,95 @@
+package cbc
+
+import (
+	"crypto/cipher"
+
+	"github.com/adavidalbertson/cryptopals/xor"
+)
+
+// cbc holds the state shared by the CBC encrypter and decrypter.
+// iv is updated after every call to CryptBlocks, so consecutive calls
+// continue the same chain.
+type cbc struct {
+	b         cipher.Block
+	blockSize int
+	iv        []byte
+}
+
+func newCBC(b cipher.Block, iv []byte) *cbc {
+	if len(iv) != b.BlockSize() {
+		panic("cbc: IV length must equal block size")
+	}
+
+	ivCopy := make([]byte, len(iv))
+	copy(ivCopy, iv)
+
+	return &cbc{b, b.BlockSize(), ivCopy}
+}
+
+type cbcEncrypter cbc
+
+// NewCBCEncrypter returns a cipher.BlockMode which encrypts in CBC mode,
+// using the given cipher.Block (such as one from ecb.NewCipher or crypto/aes).
+// Like its counterpart in crypto/cipher, it panics if the iv is the wrong length.
+// Cryptopals Set 2, Challenge 10
+// https://cryptopals.com/sets/2/challenges/10
+func NewCBCEncrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return (*cbcEncrypter)(newCBC(b, iv))
+}
+
+// BlockSize returns the block size of the underlying cipher.
+func (x *cbcEncrypter) BlockSize() int {
+	return x.blockSize
+}
+
+// CryptBlocks encrypts src into dst, which may overlap entirely.
+func (x *cbcEncrypter) CryptBlocks(dst, src []byte) {
+	if len(src)%x.blockSize != 0 {
+		panic("cbc: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("cbc: output smaller than input")
+	}
+
+	for i := 0; i < len(src); i += x.blockSize {
+		diff, _ := xor.Xor(src[i:i+x.blockSize], x.iv)
+		x.b.Encrypt(dst[i:i+x.blockSize], diff)
+		copy(x.iv, dst[i:i+x.blockSize])
+	}
+}
+
+type cbcDecrypter cbc
+
+// NewCBCDecrypter returns a cipher.BlockMode which decrypts in CBC mode,
+// using the given cipher.Block (such as one from ecb.NewCipher or crypto/aes).
+// Like its counterpart in crypto/cipher, it panics if the iv is the wrong length.
+// Cryptopals Set 2, Challenge 10
+// https://cryptopals.com/sets/2/challenges/10
+func NewCBCDecrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return (*cbcDecrypter)(newCBC(b, iv))
+}
+
+// BlockSize returns the block size of the underlying cipher.
+func (x *cbcDecrypter) BlockSize() int {
+	return x.blockSize
+}
+
+// CryptBlocks decrypts src into dst, which may overlap entirely.
+func (x *cbcDecrypter) CryptBlocks(dst, src []byte) {
+	if len(src)%x.blockSize != 0 {
+		panic("cbc: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("cbc: output smaller than input")
+	}
+
+	curBlock := make([]byte, x.blockSize)
+	for i := 0; i < len(src); i += x.blockSize {
+		// keep a copy of the ciphertext block in case dst and src overlap
+		copy(curBlock, src[i:i+x.blockSize])
+		x.b.Decrypt(dst[i:i+x.blockSize], curBlock)
+		plaintextBlock, _ := xor.Xor(dst[i:i+x.blockSize], x.iv)
+		copy(dst[i:i+x.blockSize], plaintextBlock)
+		copy(x.iv, curBlock)
+	}
+}
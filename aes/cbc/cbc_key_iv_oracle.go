@@ -0,0 +1,63 @@
+package cbc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// asciiErrorPrefix starts the error returned for plaintext with high-ASCII
+// bytes. The plaintext follows in hex.
+const asciiErrorPrefix = "Plaintext contains high-ASCII bytes: "
+
+// NewKeyAsIvOracle is like NewAesCbcOracle, but uses the key as the iv, and
+// its Decrypt rejects plaintext containing high-ASCII bytes with an error
+// that includes the offending plaintext.
+// Cryptopals Set 4, Challenge 27
+// https://cryptopals.com/sets/4/challenges/27
+func NewKeyAsIvOracle() AesCbcOracle {
+	oracle := NewAesCbcOracle()
+	oracle.key = random.Bytes(16)
+	oracle.iv = oracle.key
+	oracle.asciiOnly = true
+
+	return oracle
+}
+
+// checkAscii returns an error including plaintext if any of its bytes are
+// high-ASCII.
+func checkAscii(plaintext []byte) error {
+	for _, b := range plaintext {
+		if b > 0x7f {
+			return fmt.Errorf("%s%s", asciiErrorPrefix, hex.EncodeToString(plaintext))
+		}
+	}
+
+	return nil
+}
+
+// PlaintextFromError extracts the plaintext from an error returned by the
+// Decrypt method of a NewKeyAsIvOracle. It only relies on the error text,
+// so it also works on errors passed on by a remote oracle.
+// Cryptopals Set 4, Challenge 27
+// https://cryptopals.com/sets/4/challenges/27
+func PlaintextFromError(err error) (plaintext []byte, ok bool) {
+	if err == nil {
+		return
+	}
+
+	message := err.Error()
+	i := strings.Index(message, asciiErrorPrefix)
+	if i < 0 {
+		return
+	}
+
+	plaintext, decodeErr := hex.DecodeString(message[i+len(asciiErrorPrefix):])
+	if decodeErr != nil {
+		return nil, false
+	}
+
+	return plaintext, true
+}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 	"github.com/adavidalbertson/cryptopals/random"
 )
@@ -14,12 +15,23 @@ import (
 // https://cryptopals.com/sets/3/challenges/17
 type PaddingOracle struct {
 	plaintext, key, iv []byte
+	padder             padding.Padder
 }
 
+var _ oracle.PaddingValidator = PaddingOracle{}
+var _ oracle.CiphertextSource = PaddingOracle{}
+
 // NewPaddingOracle randomly selects a plaintext, and generates a key and iv.
+// Messages are padded with PKCS#7.
 // Cryptopals Set 3, Challenge 17
 // https://cryptopals.com/sets/3/challenges/17
 func NewPaddingOracle() PaddingOracle {
+	return NewPaddingOracleWithPadder(padding.Pkcs7Padder{})
+}
+
+// NewPaddingOracleWithPadder is like NewPaddingOracle, but validates the
+// given padding scheme instead, so the attack can be tried against it.
+func NewPaddingOracleWithPadder(padder padding.Padder) PaddingOracle {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	key := random.Bytes(16)
 	iv := random.Bytes(16)
@@ -43,7 +55,7 @@ func NewPaddingOracle() PaddingOracle {
 	// fmt.Println(plaintext)
 	// fmt.Println(string(plaintext))
 
-	return PaddingOracle{plaintext, key, iv}
+	return PaddingOracle{plaintext, key, iv, padder}
 }
 
 // Encrypt encrypts the oracle's plaintext with its key and iv.
@@ -53,7 +65,7 @@ func NewPaddingOracle() PaddingOracle {
 func (oracle PaddingOracle) Encrypt() (ciphertext, iv []byte, err error) {
 	iv = oracle.iv
 
-	padded, err := padding.Pkcs7(oracle.plaintext, 16)
+	padded, err := oracle.padder.Pad(oracle.plaintext, 16)
 	if err != nil {
 		return
 	}
@@ -64,15 +76,16 @@ func (oracle PaddingOracle) Encrypt() (ciphertext, iv []byte, err error) {
 }
 
 // Validate returns true if the plaintext was correctly padded.
+// An error means the ciphertext couldn't be decrypted at all.
 // Cryptopals Set 3, Challenge 17
 // https://cryptopals.com/sets/3/challenges/17
-func (oracle PaddingOracle) Validate(ciphertext, iv []byte) bool {
-	decrypted, _ := Decrypt(ciphertext, oracle.key, iv)
-	decrypted, err := padding.Pkcs7Unpad(decrypted)
-
-	if err == nil {
-		return true
+func (oracle PaddingOracle) Validate(ciphertext, iv []byte) (valid bool, err error) {
+	decrypted, err := Decrypt(ciphertext, oracle.key, iv)
+	if err != nil {
+		return
 	}
 
-	return false
+	_, unpadErr := oracle.padder.Unpad(decrypted)
+
+	return unpadErr == nil, nil
 }
@@ -0,0 +1,261 @@
+package cbcmac
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// ErrInvalidMac is returned for requests whose MAC doesn't verify.
+var ErrInvalidMac = errors.New("Invalid MAC")
+
+// Transfer moves Amount spacebucks from one account to another.
+type Transfer struct {
+	From, To, Amount int
+}
+
+func (t Transfer) String() string {
+	return fmt.Sprintf("%d -> %d: %d", t.From, t.To, t.Amount)
+}
+
+// Payment is one entry of a transaction list: Amount to account To.
+type Payment struct {
+	To, Amount int
+}
+
+// Bank is a toy money-transfer API, which trusts any request carrying a
+// valid CBC-MAC under a key it shares with its web client.
+//
+// Single transfers are signed with a per-request iv:
+//
+//	from=#{from_id}&to=#{to_id}&amount=#{amount} || iv || mac
+//
+// and transaction lists with the fixed zero iv:
+//
+//	from=#{from_id}&tx_list=#{to:amount(;to:amount)*} || mac
+//
+// Cryptopals Set 7, Challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+type Bank struct {
+	key       []byte
+	mu        sync.Mutex
+	transfers []Transfer
+}
+
+// NewBank returns a Bank with a random key.
+func NewBank() *Bank {
+	return &Bank{key: random.Bytes(16)}
+}
+
+// Client returns the bank's web client for the logged-in user account.
+func (b *Bank) Client(account int) Client {
+	return Client{b.key, account}
+}
+
+// Transfers returns every transfer the bank has carried out.
+func (b *Bank) Transfers() []Transfer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Transfer(nil), b.transfers...)
+}
+
+// Transfer carries out a single signed transfer, request being
+// message || iv || mac.
+func (b *Bank) Transfer(request []byte) (done []Transfer, err error) {
+	if len(request) < 2*BlockSize {
+		return nil, fmt.Errorf("Request too short: %d bytes", len(request))
+	}
+	message := request[:len(request)-2*BlockSize]
+	iv := request[len(request)-2*BlockSize : len(request)-BlockSize]
+	mac := request[len(request)-BlockSize:]
+
+	valid, err := Verify(message, b.key, iv, mac)
+	if err != nil {
+		return
+	}
+	if !valid {
+		return nil, ErrInvalidMac
+	}
+
+	params := parseParams(string(message))
+	from, err := strconv.Atoi(params["from"])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid from: %q", params["from"])
+	}
+	to, err := strconv.Atoi(params["to"])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid to: %q", params["to"])
+	}
+	amount, err := strconv.Atoi(params["amount"])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid amount: %q", params["amount"])
+	}
+
+	return b.execute([]Transfer{{from, to, amount}}), nil
+}
+
+// TransferList carries out a signed transaction list, request being
+// message || mac. Entries that don't parse are skipped.
+func (b *Bank) TransferList(request []byte) (done []Transfer, err error) {
+	if len(request) < BlockSize {
+		return nil, fmt.Errorf("Request too short: %d bytes", len(request))
+	}
+	message := request[:len(request)-BlockSize]
+	mac := request[len(request)-BlockSize:]
+
+	valid, err := Verify(message, b.key, nil, mac)
+	if err != nil {
+		return
+	}
+	if !valid {
+		return nil, ErrInvalidMac
+	}
+
+	// split on the first "&tx_list=" only: the list itself may hold anything
+	s := string(message)
+	i := strings.Index(s, "&tx_list=")
+	if !strings.HasPrefix(s, "from=") || i < 0 {
+		return nil, fmt.Errorf("Malformed transaction list: %q", s)
+	}
+	from, err := strconv.Atoi(s[len("from="):i])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid from: %q", s[len("from="):i])
+	}
+
+	var transfers []Transfer
+	for _, entry := range strings.Split(s[i+len("&tx_list="):], ";") {
+		p := strings.Split(entry, ":")
+		if len(p) != 2 {
+			continue
+		}
+		to, toErr := strconv.Atoi(p[0])
+		amount, amountErr := strconv.Atoi(p[1])
+		if toErr != nil || amountErr != nil {
+			continue
+		}
+		transfers = append(transfers, Transfer{from, to, amount})
+	}
+
+	return b.execute(transfers), nil
+}
+
+func (b *Bank) execute(transfers []Transfer) []Transfer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.transfers = append(b.transfers, transfers...)
+
+	return transfers
+}
+
+// ServeHTTP accepts requests POSTed as the raw request bytes: single
+// transfers to /transfer and transaction lists to /tx_list.
+// It answers 200 with the transfers carried out, one per line, 403 if the
+// MAC is invalid, and 400 if the request is malformed.
+func (b *Bank) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var execute func([]byte) ([]Transfer, error)
+	switch r.URL.Path {
+	case "/transfer":
+		execute = b.Transfer
+	case "/tx_list":
+		execute = b.TransferList
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	request, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	done, err := execute(request)
+	if err == ErrInvalidMac {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, t := range done {
+		fmt.Fprintln(w, t)
+	}
+}
+
+func parseParams(s string) map[string]string {
+	params := make(map[string]string)
+	for _, pair := range strings.Split(s, "&") {
+		p := strings.SplitN(pair, "=", 2)
+		if len(p) == 2 {
+			params[p[0]] = p[1]
+		}
+	}
+
+	return params
+}
+
+// Client is the bank's web client, logged in as one account. It shares the
+// bank's key, and only signs requests from its own account.
+type Client struct {
+	key     []byte
+	account int
+}
+
+var _ oracle.MacSigner = Client{}
+
+// Request signs a single transfer from the client's account, with a random iv.
+func (c Client) Request(to, amount int) (request []byte, err error) {
+	message := []byte(fmt.Sprintf("from=%d&to=%d&amount=%d", c.account, to, amount))
+	iv := random.Bytes(BlockSize)
+
+	mac, err := Sum(message, c.key, iv)
+	if err != nil {
+		return
+	}
+
+	request = append(message, iv...)
+
+	return append(request, mac...), nil
+}
+
+// RequestList signs a transaction list from the client's account.
+func (c Client) RequestList(payments []Payment) (request []byte, err error) {
+	entries := make([]string, len(payments))
+	for i, p := range payments {
+		entries[i] = fmt.Sprintf("%d:%d", p.To, p.Amount)
+	}
+	message := []byte(fmt.Sprintf("from=%d&tx_list=%s", c.account, strings.Join(entries, ";")))
+
+	mac, err := c.Sign(message)
+	if err != nil {
+		return
+	}
+
+	return append(message, mac...), nil
+}
+
+// Sign returns the fixed-iv CBC-MAC of a transaction list message, if it is
+// from the client's account.
+func (c Client) Sign(message []byte) (mac []byte, err error) {
+	prefix := fmt.Sprintf("from=%d&tx_list=", c.account)
+	if !strings.HasPrefix(string(message), prefix) {
+		return nil, fmt.Errorf("Client can only sign transaction lists from account %d", c.account)
+	}
+
+	return Sum(message, c.key, nil)
+}
@@ -0,0 +1,125 @@
+package cbcmac
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBank_Transfer(t *testing.T) {
+	bank := NewBank()
+	request, _ := bank.Client(1).Request(2, 100)
+
+	tampered := append([]byte{}, request...)
+	tampered[len("from=1&to=2&amount=")] = '9'
+
+	tests := []struct {
+		name    string
+		request []byte
+		want    []Transfer
+		wantErr bool
+	}{
+		{"valid", request, []Transfer{{1, 2, 100}}, false},
+		{"tampered", tampered, nil, true},
+		{"too_short", request[:20], nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bank.Transfer(tt.request)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bank.Transfer() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bank.Transfer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBank_TransferList(t *testing.T) {
+	bank := NewBank()
+	request, _ := bank.Client(1).RequestList([]Payment{{2, 100}, {3, 50}})
+
+	got, err := bank.TransferList(request)
+	if err != nil {
+		t.Errorf("Bank.TransferList() error = %v", err)
+		return
+	}
+	want := []Transfer{{1, 2, 100}, {1, 3, 50}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bank.TransferList() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(bank.Transfers(), want) {
+		t.Errorf("Bank.Transfers() = %v, want %v", bank.Transfers(), want)
+	}
+
+	request[len(request)-1] ^= 1
+	if _, err := bank.TransferList(request); err != ErrInvalidMac {
+		t.Errorf("Bank.TransferList() with bad MAC error = %v, want %v", err, ErrInvalidMac)
+	}
+}
+
+func TestClient_Sign(t *testing.T) {
+	client := NewBank().Client(1)
+
+	tests := []struct {
+		name    string
+		message string
+		wantErr bool
+	}{
+		{"own_account", "from=1&tx_list=2:100", false},
+		{"other_account", "from=2&tx_list=1:100", true},
+		{"account_prefix", "from=12&tx_list=1:100", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Sign([]byte(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Sign() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBank_ServeHTTP(t *testing.T) {
+	bank := NewBank()
+	server := httptest.NewServer(bank)
+	defer server.Close()
+
+	request, _ := bank.Client(1).Request(2, 100)
+	list, _ := bank.Client(1).RequestList([]Payment{{3, 50}})
+	forged := append([]byte{}, request...)
+	forged[len(forged)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		path       string
+		request    []byte
+		wantStatus int
+		wantBody   string
+	}{
+		{"transfer", "/transfer", request, http.StatusOK, "1 -> 2: 100\n"},
+		{"tx_list", "/tx_list", list, http.StatusOK, "1 -> 3: 50\n"},
+		{"invalid_mac", "/transfer", forged, http.StatusForbidden, "Invalid MAC\n"},
+		{"not_found", "/withdraw", request, http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+tt.path, "application/octet-stream", bytes.NewReader(tt.request))
+			if err != nil {
+				t.Errorf("http.Post() error = %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+				t.Errorf("Bank.ServeHTTP() = %d %q, want %d %q", resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
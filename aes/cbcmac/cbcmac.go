@@ -0,0 +1,62 @@
+// Package cbcmac implements AES CBC-MAC: the last block of the CBC encryption
+// of a PKCS#7 padded message.
+// Cryptopals Set 7, Challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+package cbcmac
+
+import (
+	"crypto/subtle"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/padding"
+)
+
+// BlockSize is the size of an AES block, and of a CBC-MAC.
+const BlockSize = 16
+
+// Sum returns the CBC-MAC of message under key, starting from iv.
+// A nil iv is the fixed all-zero iv; passing the iv along with the message
+// lets the sender choose it, which is the variant attacked in Challenge 49.
+// Cryptopals Set 7, Challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+func Sum(message, key, iv []byte) (mac []byte, err error) {
+	// copy first: Pkcs7 appends in place, and message is often a slice of
+	// a request with the iv and MAC following it
+	padded, err := padding.Pkcs7(append([]byte(nil), message...), BlockSize)
+	if err != nil {
+		return
+	}
+
+	ciphertext, err := cbc.Encrypt(padded, key, iv)
+	if err != nil {
+		return
+	}
+
+	return ciphertext[len(ciphertext)-BlockSize:], nil
+}
+
+// Verify returns true if mac is the CBC-MAC of message under key and iv,
+// comparing in constant time.
+func Verify(message, key, iv, mac []byte) (valid bool, err error) {
+	want, err := Sum(message, key, iv)
+	if err != nil {
+		return
+	}
+
+	return subtle.ConstantTimeCompare(want, mac) == 1, nil
+}
+
+// HashKey is the public key of Hash.
+var HashKey = []byte("YELLOW SUBMARINE")
+
+// Hash is CBC-MAC used as a hash function: the key and the (zero) iv are
+// public. Anyone can then compute MACs, and run the cipher backwards too,
+// which is why this is not a hash function.
+// Cryptopals Set 7, Challenge 50
+// https://cryptopals.com/sets/7/challenges/50
+func Hash(message []byte) []byte {
+	// the key is valid, so Sum can't fail
+	digest, _ := Sum(message, HashKey, nil)
+
+	return digest
+}
@@ -0,0 +1,105 @@
+package cbcmac
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		message []byte
+		key     []byte
+		iv      []byte
+		want    []byte
+	}{
+		// Cryptopals Set 7, Challenge 50
+		{"challenge_50", []byte("alert('MZA who was that?');\n"), []byte("YELLOW SUBMARINE"), nil, decodeHex("296b8d7cb78a243dda4d0a61d33bbdd1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Sum(tt.message, tt.key, tt.iv)
+			if err != nil {
+				t.Errorf("Sum() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sum() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSum_matchCryptoCipher(t *testing.T) {
+	key, iv := random.Bytes(16), random.Bytes(16)
+	for _, length := range []int{0, 15, 16, 100} {
+		message := random.Bytes(length)
+
+		padded, _ := padding.Pkcs7(message, BlockSize)
+		block, _ := aes.NewCipher(key)
+		ciphertext := make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+		want := ciphertext[len(ciphertext)-BlockSize:]
+
+		got, err := Sum(message, key, iv)
+		if err != nil {
+			t.Errorf("Sum() error = %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Sum() of %d bytes = %x, want %x", length, got, want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	key, iv := random.Bytes(16), random.Bytes(16)
+	message := []byte("from=1&to=2&amount=100")
+	mac, _ := Sum(message, key, iv)
+
+	tests := []struct {
+		name    string
+		message []byte
+		iv      []byte
+		want    bool
+	}{
+		{"valid", message, iv, true},
+		{"wrong_iv", message, nil, false},
+		{"tampered_message", []byte("from=1&to=2&amount=900"), iv, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Verify(tt.message, key, tt.iv, mac)
+			if err != nil {
+				t.Errorf("Verify() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	// Cryptopals Set 7, Challenge 50
+	want := decodeHex("296b8d7cb78a243dda4d0a61d33bbdd1")
+	if got := Hash([]byte("alert('MZA who was that?');\n")); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hash() = %x, want %x", got, want)
+	}
+}
@@ -1,61 +1,71 @@
 package ctr
 
 import (
-	"encoding/binary"
 	"fmt"
+
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
-	"github.com/adavidalbertson/cryptopals/xor"
+	"github.com/adavidalbertson/cryptopals/oracle"
 )
 
-// AesCtrCipher stores the key, nonce, and counter for AES CTR encryption.
+// AesCtrCipher stores the key, nonce, counter, and counter format for AES CTR encryption.
 // Cryptopals Set 3, Challenge 18
 // https://cryptopals.com/sets/3/challenges/18
 type AesCtrCipher struct {
 	key, nonce []byte
 	counter    uint64
+	format     CounterFormat
 }
 
-// NewAesCtrCipher sets initial values for key, nonce, and counter
+var _ oracle.EditOracle = &AesCtrCipher{}
+
+// NewAesCtrCipher sets initial values for key, nonce, and counter.
+// It uses the 64-bit little-endian counter specified by Cryptopals.
 // Cryptopals Set 3, Challenge 18
 // https://cryptopals.com/sets/3/challenges/18
 func NewAesCtrCipher(key, nonce []byte) (cipher AesCtrCipher, err error) {
+	return NewAesCtrCipherWithFormat(key, nonce, LittleEndian64)
+}
+
+// NewAesCtrCipherWithFormat sets initial values for key, nonce, and counter,
+// using the given counter format. A nil nonce is replaced with zeros.
+func NewAesCtrCipherWithFormat(key, nonce []byte, format CounterFormat) (cipher AesCtrCipher, err error) {
+	blockSize := 16
 	counter := uint64(0)
+	if format < LittleEndian64 || format > BigEndian32 {
+		err = fmt.Errorf("Unknown counter format: %d", format)
+		return
+	}
+
 	if nonce == nil {
-		nonce = make([]byte, 8)
-	} else if len(nonce) != 8 {
+		nonce = make([]byte, format.NonceSize(blockSize))
+	} else if len(nonce) != format.NonceSize(blockSize) {
 		err = fmt.Errorf("Nonce has invalid length")
 		return
 	}
 
-	if len(key) != 16 {
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
 		err = fmt.Errorf("Key has invalid length")
 		return
 	}
 
-	return AesCtrCipher{key, nonce, counter}, nil
+	return AesCtrCipher{key, nonce, counter, format}, nil
 }
 
 // Encrypt the plaintext using the keystream generated by the cipher.
 // Cryptopals Set 3, Challenge 18
 // https://cryptopals.com/sets/3/challenges/18
 func (cipher *AesCtrCipher) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
-	blockSize := 16
-	var keystream []byte
-
-	for i := 0; i <= len(plaintext); i += blockSize {
-		counterBytes := make([]byte, 8)
-		binary.PutUvarint(counterBytes, cipher.counter)
-		next, err := ecb.Encrypt(append(cipher.nonce, counterBytes...), cipher.key)
-		if err != nil {
-			return ciphertext, err
-		}
-
-		keystream = append(keystream, next...)
-		cipher.counter++
+	block, err := ecb.NewCipher(cipher.key)
+	if err != nil {
+		return
 	}
 
-	keystream = keystream[:len(plaintext)]
-	ciphertext, err = xor.Xor(plaintext, keystream)
+	stream := newCTR(block, cipher.nonce, cipher.counter, cipher.format)
+	ciphertext = make([]byte, len(plaintext))
+	stream.XORKeyStream(ciphertext, plaintext)
+
+	// any leftover keystream is discarded, so the next call starts on a fresh block
+	cipher.counter = stream.counter
 
 	return
 }
@@ -78,7 +88,7 @@ func (cipher *AesCtrCipher) Edit(ciphertext, newText []byte, offset int) (newCip
 		return ciphertext, fmt.Errorf("Offset (%d) exceeds ciphertext length (%d).", offset*blockSize, len(ciphertext))
 	}
 
-	tempCipher, err := NewAesCtrCipher(cipher.key, cipher.nonce)
+	tempCipher, err := NewAesCtrCipherWithFormat(cipher.key, cipher.nonce, cipher.format)
 	if err != nil {
 		return
 	}
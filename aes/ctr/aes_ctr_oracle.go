@@ -0,0 +1,74 @@
+package ctr
+
+import (
+	"strings"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// AesCtrOracle is the CTR counterpart of cbc.AesCbcOracle. It contains a
+// prefix, suffix, key, and nonce for encryption.
+// Cryptopals Set 4, Challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+type AesCtrOracle struct {
+	prefix, suffix string
+	key, nonce     []byte
+}
+
+var _ oracle.EncryptionOracle = AesCtrOracle{}
+var _ oracle.AdminChecker = AesCtrOracle{}
+
+// NewAesCtrOracle sets the same hardcoded prefix and suffix as
+// cbc.NewAesCbcOracle, and a random key and nonce.
+// Cryptopals Set 4, Challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+func NewAesCtrOracle() AesCtrOracle {
+	prefix := "comment1=cooking%20MCs;userdata="
+	suffix := ";comment2=%20like%20a%20pound%20of%20bacon"
+
+	key := random.Bytes(16)
+	nonce := random.Bytes(LittleEndian64.NonceSize(16))
+
+	return AesCtrOracle{prefix, suffix, key, nonce}
+}
+
+// Encrypt quotes ';' and '=' in the plaintext, wraps it in the oracle's
+// prefix and suffix, and encrypts with the oracle's key and nonce.
+// Cryptopals Set 4, Challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+func (oracle AesCtrOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	userdata := string(plaintext)
+	userdata = strings.Replace(userdata, ";", "%3B", -1)
+	userdata = strings.Replace(userdata, "=", "%3D", -1)
+
+	cipher, err := NewAesCtrCipher(oracle.key, oracle.nonce)
+	if err != nil {
+		return
+	}
+
+	return cipher.Encrypt([]byte(oracle.prefix + userdata + oracle.suffix))
+}
+
+// Decrypt takes an encrypted user token, and returns true if the admin parameter is true.
+// Cryptopals Set 4, Challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+func (oracle AesCtrOracle) Decrypt(ciphertext []byte) (isAdmin bool, err error) {
+	cipher, err := NewAesCtrCipher(oracle.key, oracle.nonce)
+	if err != nil {
+		return
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return
+	}
+
+	for _, pair := range strings.Split(string(plaintext), ";") {
+		if pair == "admin=true" {
+			return true, nil
+		}
+	}
+
+	return
+}
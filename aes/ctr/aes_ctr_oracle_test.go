@@ -0,0 +1,69 @@
+package ctr
+
+import (
+	"testing"
+)
+
+func TestAesCtrOracle_Encrypt(t *testing.T) {
+	tests := []struct {
+		name      string
+		plaintext string
+		wantAdmin bool
+	}{
+		{"plain_userdata", "some data", false},
+		{"challenge_26_hack_attempt", "some data;admin=true", false},
+		{"quoted_hack_attempt", "x;admin=true;", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := NewAesCtrOracle()
+			ciphertext, err := oracle.Encrypt([]byte(tt.plaintext))
+			if err != nil {
+				t.Errorf("AesCtrOracle.Encrypt() error = %v", err)
+				return
+			}
+			if len(ciphertext) != len(oracle.prefix)+len(tt.plaintext)+len(oracle.suffix)+2*countQuoted(tt.plaintext) {
+				t.Errorf("AesCtrOracle.Encrypt() length = %d, want quoted userdata", len(ciphertext))
+			}
+
+			gotAdmin, err := oracle.Decrypt(ciphertext)
+			if err != nil {
+				t.Errorf("AesCtrOracle.Decrypt() error = %v", err)
+				return
+			}
+			if gotAdmin != tt.wantAdmin {
+				t.Errorf("AesCtrOracle.Decrypt() = %v, want %v", gotAdmin, tt.wantAdmin)
+			}
+		})
+	}
+}
+
+func TestAesCtrOracle_Decrypt(t *testing.T) {
+	oracle := NewAesCtrOracle()
+	cipher, err := NewAesCtrCipher(oracle.key, oracle.nonce)
+	if err != nil {
+		t.Errorf("NewAesCtrCipher() error = %v", err)
+		return
+	}
+
+	// a token the oracle would never produce, encrypted under its key
+	token, _ := cipher.Encrypt([]byte(oracle.prefix + "x;admin=true" + oracle.suffix))
+	isAdmin, err := oracle.Decrypt(token)
+	if err != nil {
+		t.Errorf("AesCtrOracle.Decrypt() error = %v", err)
+		return
+	}
+	if !isAdmin {
+		t.Errorf("AesCtrOracle.Decrypt() = %v, want %v", isAdmin, true)
+	}
+}
+
+func countQuoted(s string) (n int) {
+	for _, c := range s {
+		if c == ';' || c == '=' {
+			n++
+		}
+	}
+
+	return
+}
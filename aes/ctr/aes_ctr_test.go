@@ -0,0 +1,190 @@
+package ctr
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// NIST SP 800-38A, Appendix F.5
+const sp80038aPlaintext = "6bc1bee22e409f96e93d7e117393172a" +
+	"ae2d8a571e03ac9c9eb76fac45af8e51" +
+	"30c81c46a35ce411e5fbc1191a0a52ef" +
+	"f69f2445df4f9b17ad2b417be66c3710"
+
+const sp80038aCounter = "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"
+
+func TestAesCtrCipher_Encrypt(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		type args struct {
+			key, nonce, plaintext []byte
+			format                CounterFormat
+		}
+		tests := []struct {
+			name           string
+			args           args
+			wantCiphertext []byte
+		}{
+			{
+				"sp800_38a_f_5_1_ctr_aes128",
+				args{
+					decodeHex("2b7e151628aed2a6abf7158809cf4f3c"),
+					decodeHex(sp80038aCounter),
+					decodeHex(sp80038aPlaintext),
+					BigEndian128,
+				},
+				decodeHex("874d6191b620e3261bef6864990db6ce" +
+					"9806f66b7970fdff8617187bb9fffdff" +
+					"5ae4df3edbd5d35e5b4f09020db03eab" +
+					"1e031dda2fbe03d1792170a0f3009cee"),
+			},
+			{
+				"sp800_38a_f_5_3_ctr_aes192",
+				args{
+					decodeHex("8e73b0f7da0e6452c810f32b809079e562f8ead2522c6b7b"),
+					decodeHex(sp80038aCounter),
+					decodeHex(sp80038aPlaintext),
+					BigEndian128,
+				},
+				decodeHex("1abc932417521ca24f2b0459fe7e6e0b" +
+					"090339ec0aa6faefd5ccc2c6f4ce8e94" +
+					"1e36b26bd1ebc670d1bd1d665620abf7" +
+					"4f78a7f6d29809585a97daec58c6b050"),
+			},
+			{
+				"sp800_38a_f_5_5_ctr_aes256",
+				args{
+					decodeHex("603deb1015ca71be2b73aef0857d77811f352c073b6108d72d9810a30914dff4"),
+					decodeHex(sp80038aCounter),
+					decodeHex(sp80038aPlaintext),
+					BigEndian128,
+				},
+				decodeHex("601ec313775789a5b7a7f504bbf3d228" +
+					"f443e3ca4d62b59aca84e990cacaf5c5" +
+					"2b0930daa23de94ce87017ba2d84988d" +
+					"dfc9c58db67aada613c2dd08457941a6"),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				cipher, err := NewAesCtrCipherWithFormat(tt.args.key, tt.args.nonce, tt.args.format)
+				if err != nil {
+					t.Errorf("NewAesCtrCipherWithFormat() error = %v", err)
+					return
+				}
+
+				gotCiphertext, err := cipher.Encrypt(tt.args.plaintext)
+				if err != nil {
+					t.Errorf("AesCtrCipher.Encrypt() error = %v", err)
+					return
+				}
+				if !reflect.DeepEqual(gotCiphertext, tt.wantCiphertext) {
+					t.Errorf("AesCtrCipher.Encrypt() = %x, want %x", gotCiphertext, tt.wantCiphertext)
+				}
+			})
+		}
+	})
+}
+
+func TestAesCtrCipher_Decrypt(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		t.Run("challenge_18", func(t *testing.T) {
+			ciphertext, _ := base64.StdEncoding.DecodeString("L77na/nrFsKvynd6HzOoG7GHTLXsTVu9qvY/2syLXzhPweyyMTJULu/6/kXX0KSvoOLSFQ==")
+			want := "Yo, VIP Let's kick it Ice, Ice, baby Ice, Ice, baby "
+
+			cipher, err := NewAesCtrCipher([]byte("YELLOW SUBMARINE"), nil)
+			if err != nil {
+				t.Errorf("NewAesCtrCipher() error = %v", err)
+				return
+			}
+
+			got, err := cipher.Decrypt(ciphertext)
+			if err != nil {
+				t.Errorf("AesCtrCipher.Decrypt() error = %v", err)
+				return
+			}
+			if string(got) != want {
+				t.Errorf("AesCtrCipher.Decrypt() = %q, want %q", got, want)
+			}
+		})
+	})
+}
+
+func TestNewCTRWithFormat(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []struct {
+			name  string
+			nonce []byte
+		}{
+			{"random", random.Bytes(16)},
+			{"low_half_overflow", decodeHex("0123456789abcdeffffffffffffffffe")},
+			{"full_overflow", decodeHex("fffffffffffffffffffffffffffffffe")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				block, err := aes.NewCipher(random.Bytes(16))
+				if err != nil {
+					t.Errorf("aes.NewCipher() error = %v", err)
+					return
+				}
+				plaintext := random.Bytes(100)
+
+				got := make([]byte, len(plaintext))
+				stream := NewCTRWithFormat(block, tt.nonce, BigEndian128)
+				// split the input to make sure the keystream carries over between calls
+				stream.XORKeyStream(got[:37], plaintext[:37])
+				stream.XORKeyStream(got[37:], plaintext[37:])
+
+				want := make([]byte, len(plaintext))
+				cipher.NewCTR(block, tt.nonce).XORKeyStream(want, plaintext)
+
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("NewCTRWithFormat().XORKeyStream() = %x, want %x", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestCounterFormat_counterBlock(t *testing.T) {
+	nonce := decodeHex("0001020304050607")
+	tests := []struct {
+		name    string
+		format  CounterFormat
+		nonce   []byte
+		counter uint64
+		want    []byte
+	}{
+		{"little_endian_64", LittleEndian64, nonce, 0x0102, decodeHex("00010203040506070201000000000000")},
+		{"big_endian_64", BigEndian64, nonce, 0x0102, decodeHex("00010203040506070000000000000102")},
+		{"big_endian_64_max", BigEndian64, nonce, 0xffffffffffffffff, decodeHex("0001020304050607ffffffffffffffff")},
+		{"big_endian_128", BigEndian128, decodeHex("000102030405060708090a0b0c0d0e0f"), 0x0102, decodeHex("000102030405060708090a0b0c0d0f11")},
+		{"big_endian_128_carry", BigEndian128, decodeHex("00000000000000ffffffffffffffffff"), 1, decodeHex("00000000000001000000000000000000")},
+		{"big_endian_32_wraps", BigEndian32, decodeHex("000102030405060708090a0bffffffff"), 2, decodeHex("000102030405060708090a0b00000001")},
+		{"big_endian_128_wraps", BigEndian128, decodeHex("ffffffffffffffffffffffffffffffff"), 1, decodeHex("00000000000000000000000000000000")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]byte, 16)
+			tt.format.counterBlock(got, tt.nonce, tt.counter)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CounterFormat.counterBlock() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,15 +2,67 @@ package ecb
 
 import (
 	"crypto/aes"
+	"crypto/cipher"
+
+	"github.com/adavidalbertson/cryptopals/aes/rijndael"
 )
 
+// Backend creates the AES block cipher for a key.
+type Backend func(keyBytes []byte) (cipher.Block, error)
+
+// StdlibBackend uses AES from the crypto package.
+func StdlibBackend(keyBytes []byte) (cipher.Block, error) {
+	return aes.NewCipher(keyBytes)
+}
+
+// PureGoBackend uses the from-scratch AES in aes/rijndael.
+// To observe or tamper with round states, wrap rijndael.NewCipher in a
+// Backend which sets the hooks before returning the cipher.
+func PureGoBackend(keyBytes []byte) (cipher.Block, error) {
+	c, err := rijndael.NewCipher(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NamedBackend is a Backend with a name to report it by.
+type NamedBackend struct {
+	Name    string
+	Backend Backend
+}
+
+// Backends lists every Backend, for running the same code against each.
+var Backends = []NamedBackend{
+	{"stdlib", StdlibBackend},
+	{"purego", PureGoBackend},
+}
+
+var backend Backend = StdlibBackend
+
+// SetBackend selects the block cipher used by Encrypt, Decrypt, and every
+// mode built on NewCipher, and returns the previous one.
+// It is not safe to call while other goroutines are encrypting.
+func SetBackend(b Backend) (previous Backend) {
+	previous, backend = backend, b
+	return
+}
+
+// NewCipher returns the block cipher used by Encrypt and Decrypt.
+// The other modes of operation are built on top of this, so they can be
+// pointed at any cipher.Block that behaves the same way.
+func NewCipher(keyBytes []byte) (cipher.Block, error) {
+	return backend(keyBytes)
+}
+
 // Encrypt bytes using AES in the ECB mode of operation.
-// Force AES from the crypto package to run in ECB mode by working on one block at a time.
+// Force the AES block cipher to run in ECB mode by working on one block at a time.
 // This will be used as the basis for all the other AES modes of operation.
 // Cryptopals Set 1, Challenge 7
 // https://cryptopals.com/sets/1/challenges/7
 func Encrypt(plaintextBytes, keyBytes []byte) (ciphertextBytes []byte, err error) {
-	cipher, err := aes.NewCipher(keyBytes)
+	cipher, err := NewCipher(keyBytes)
 	if err != nil {
 		return
 	}
@@ -28,12 +80,12 @@ func Encrypt(plaintextBytes, keyBytes []byte) (ciphertextBytes []byte, err error
 }
 
 // Decrypt bytes using AES in the ECB mode of operation.
-// Force AES from the crypto package to run in ECB mode by working on one block at a time.
+// Force the AES block cipher to run in ECB mode by working on one block at a time.
 // This will be used as the basis for all the other AES modes of operation.
 // Cryptopals Set 1, Challenge 7
 // https://cryptopals.com/sets/1/challenges/7
 func Decrypt(ciphertextBytes, keyBytes []byte) (plaintextBytes []byte, err error) {
-	cipher, err := aes.NewCipher(keyBytes)
+	cipher, err := NewCipher(keyBytes)
 	if err != nil {
 		return
 	}
@@ -5,25 +5,30 @@ import (
 	mrand "math/rand"
 	"time"
 
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 	"github.com/adavidalbertson/cryptopals/random"
 )
 
-// EncryptionOracle is an interface containing only an Encrypt function
-type EncryptionOracle interface {
-	Encrypt([]byte) []byte
-}
-
 // AesEcbOracle contains an unknown key, optional prefix, and suffix for encryption.
 type AesEcbOracle struct {
 	prefix, suffix, key []byte
+	padder              padding.Padder
 }
 
+var _ oracle.EncryptionOracle = AesEcbOracle{}
+
 // NewAesEcbOracle returns a new AesEcbOracle that uses the specified suffix,
 // a random key, and if desired, a random prefix (for Challenge 14).
+// Messages are padded with PKCS#7.
 // Cryptopals Set 2, Challenge 12
 // https://cryptopals.com/sets/2/challenges/12
 func NewAesEcbOracle(suffix []byte, addPrefix bool) (AesEcbOracle, error) {
+	return NewAesEcbOracleWithPadder(suffix, addPrefix, padding.Pkcs7Padder{})
+}
+
+// NewAesEcbOracleWithPadder is like NewAesEcbOracle, but uses the given padding scheme.
+func NewAesEcbOracleWithPadder(suffix []byte, addPrefix bool, padder padding.Padder) (AesEcbOracle, error) {
 	r := mrand.New(mrand.NewSource(time.Now().UnixNano()))
 	key := make([]byte, 16)
 
@@ -34,7 +39,7 @@ func NewAesEcbOracle(suffix []byte, addPrefix bool) (AesEcbOracle, error) {
 	}
 
 	_, err := crand.Read(key)
-	return AesEcbOracle{prefix, suffix, key}, err
+	return AesEcbOracle{prefix, suffix, key, padder}, err
 }
 
 // Encrypt appends the oracle's prefix (if any) and suffix to the plaintext.
@@ -42,7 +47,7 @@ func NewAesEcbOracle(suffix []byte, addPrefix bool) (AesEcbOracle, error) {
 func (oracle AesEcbOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
 	plaintext = append(oracle.prefix, plaintext...)
 	plaintext = append(plaintext, oracle.suffix...)
-	plaintext, err = padding.Pkcs7(plaintext, 16)
+	plaintext, err = oracle.padder.Pad(plaintext, 16)
 	if err != nil {
 		return
 	}
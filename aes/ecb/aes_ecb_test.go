@@ -8,6 +8,19 @@ import (
 	"github.com/adavidalbertson/cryptopals/fileutils"
 )
 
+// forEachBackend is ecbtest.ForEachBackend, which this package's tests
+// can't import without a cycle.
+func forEachBackend(t *testing.T, f func(t *testing.T)) {
+	for _, b := range Backends {
+		t.Run(b.Name, func(t *testing.T) {
+			previous := SetBackend(b.Backend)
+			t.Cleanup(func() { SetBackend(previous) })
+
+			f(t)
+		})
+	}
+}
+
 type args struct {
 	input    []byte
 	keyBytes []byte
@@ -39,49 +52,53 @@ func newTestCaseFromFiles(name, inputPath, outputPath, key string, wantErr bool)
 }
 
 func TestDecrypt(t *testing.T) {
-	tests := []testCase{
-		newTestCaseFromFiles("challenge_7",
-			"../../challenges/set_1/challenge_7/input.txt",
-			"../../challenges/set_1/challenge_7/output_base64.txt",
-			"YELLOW SUBMARINE",
-			false,
-		),
-	}
+	forEachBackend(t, func(t *testing.T) {
+		tests := []testCase{
+			newTestCaseFromFiles("challenge_7",
+				"../../challenges/set_1/challenge_7/input.txt",
+				"../../challenges/set_1/challenge_7/output_base64.txt",
+				"YELLOW SUBMARINE",
+				false,
+			),
+		}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotPlaintextBytes, err := Decrypt(tt.args.input, tt.args.keyBytes)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotPlaintextBytes, tt.wantOutput) {
-				t.Errorf("Decrypt() = %v, want %v", gotPlaintextBytes, tt.wantOutput)
-			}
-		})
-	}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				gotPlaintextBytes, err := Decrypt(tt.args.input, tt.args.keyBytes)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(gotPlaintextBytes, tt.wantOutput) {
+					t.Errorf("Decrypt() = %v, want %v", gotPlaintextBytes, tt.wantOutput)
+				}
+			})
+		}
+	})
 }
 
 func TestEncrypt(t *testing.T) {
-	tests := []testCase{
-		newTestCaseFromFiles("challenge_7",
-			"../../challenges/set_1/challenge_7/output_base64.txt",
-			"../../challenges/set_1/challenge_7/input.txt",
-			"YELLOW SUBMARINE",
-			false,
-		),
-	}
+	forEachBackend(t, func(t *testing.T) {
+		tests := []testCase{
+			newTestCaseFromFiles("challenge_7",
+				"../../challenges/set_1/challenge_7/output_base64.txt",
+				"../../challenges/set_1/challenge_7/input.txt",
+				"YELLOW SUBMARINE",
+				false,
+			),
+		}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotCiphertextBytes, err := Encrypt(tt.args.input, tt.args.keyBytes)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(gotCiphertextBytes, tt.wantOutput) {
-				t.Errorf("Encrypt() = %v, want %v", gotCiphertextBytes, tt.wantOutput)
-			}
-		})
-	}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				gotCiphertextBytes, err := Encrypt(tt.args.input, tt.args.keyBytes)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(gotCiphertextBytes, tt.wantOutput) {
+					t.Errorf("Encrypt() = %v, want %v", gotCiphertextBytes, tt.wantOutput)
+				}
+			})
+		}
+	})
 }
@@ -0,0 +1,23 @@
+// Package ecbtest runs tests against every AES backend in package ecb.
+package ecbtest
+
+import (
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+)
+
+// ForEachBackend runs f as a subtest once for each of ecb.Backends, with
+// that backend selected, and puts the previous backend back afterwards.
+// The backend is global, so f must not run in parallel with other tests
+// that encrypt.
+func ForEachBackend(t *testing.T, f func(t *testing.T)) {
+	for _, b := range ecb.Backends {
+		t.Run(b.Name, func(t *testing.T) {
+			previous := ecb.SetBackend(b.Backend)
+			t.Cleanup(func() { ecb.SetBackend(previous) })
+
+			f(t)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 )
 
@@ -41,6 +42,8 @@ type ProfileMaker struct {
 	key []byte
 }
 
+var _ oracle.ProfileOracle = ProfileMaker{}
+
 // NewProfileMaker generates a random key to encrypt profiles.
 func NewProfileMaker() ProfileMaker {
 	key := make([]byte, 16)
@@ -0,0 +1,167 @@
+package gcm
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+)
+
+const (
+	blockSize        = 16
+	defaultNonceSize = 12
+	defaultTagSize   = 16
+	minTagSize       = 4
+)
+
+// ErrOpen is returned by Open when the tag does not match.
+// It says nothing about why, so it can't be used as an oracle.
+var ErrOpen = errors.New("gcm: message authentication failed")
+
+var _ cipher.AEAD = (*GCM)(nil)
+
+// GCM is AES in Galois/Counter Mode. It implements cipher.AEAD, and also
+// exposes the hash subkey and GHASH so the internals can be attacked.
+// NIST SP 800-38D
+type GCM struct {
+	block     cipher.Block
+	h         [16]byte
+	nonceSize int
+	tagSize   int
+}
+
+// NewAesGCM returns AES-GCM with a 12-byte nonce and a 16-byte tag,
+// using the block cipher from ecb.NewCipher.
+func NewAesGCM(key []byte) (*GCM, error) {
+	block, err := ecb.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGCM(block)
+}
+
+// NewGCM returns GCM over the given 128-bit block cipher, with a 12-byte
+// nonce and a 16-byte tag.
+func NewGCM(block cipher.Block) (*GCM, error) {
+	return NewGCMWithNonceAndTagSize(block, defaultNonceSize, defaultTagSize)
+}
+
+// NewGCMWithNonceAndTagSize returns GCM with the given nonce and tag sizes.
+// Nonces other than 12 bytes are hashed to make the initial counter block.
+// Tags may be 4 to 16 bytes, though anything under 12 is only approved for
+// special uses (NIST SP 800-38D, Appendix C).
+func NewGCMWithNonceAndTagSize(block cipher.Block, nonceSize, tagSize int) (*GCM, error) {
+	if block.BlockSize() != blockSize {
+		return nil, fmt.Errorf("GCM requires a 128-bit block cipher")
+	}
+
+	if nonceSize <= 0 {
+		return nil, fmt.Errorf("Invalid nonce size: %d", nonceSize)
+	}
+
+	if tagSize < minTagSize || tagSize > blockSize {
+		return nil, fmt.Errorf("Invalid tag size: %d", tagSize)
+	}
+
+	var h [16]byte
+	block.Encrypt(h[:], h[:])
+
+	return &GCM{block, h, nonceSize, tagSize}, nil
+}
+
+// H returns the hash subkey, the encryption of the zero block.
+func (g *GCM) H() [16]byte {
+	return g.h
+}
+
+// NonceSize returns the nonce size that Seal and Open expect.
+func (g *GCM) NonceSize() int {
+	return g.nonceSize
+}
+
+// Overhead returns the length of the tag.
+func (g *GCM) Overhead() int {
+	return g.tagSize
+}
+
+// Seal encrypts and authenticates plaintext, authenticates additionalData,
+// and appends the ciphertext and tag to dst.
+// NIST SP 800-38D, Algorithm 4
+func (g *GCM) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != g.nonceSize {
+		panic("gcm: incorrect nonce length given to GCM")
+	}
+
+	j0 := g.initialCounterBlock(nonce)
+
+	ciphertext := make([]byte, len(plaintext))
+	g.keystream(j0).XORKeyStream(ciphertext, plaintext)
+
+	tag := g.tag(j0, additionalData, ciphertext)
+
+	dst = append(dst, ciphertext...)
+	return append(dst, tag...)
+}
+
+// Open authenticates ciphertext and additionalData, and if they are genuine,
+// decrypts ciphertext and appends the plaintext to dst.
+// NIST SP 800-38D, Algorithm 5
+func (g *GCM) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != g.nonceSize {
+		panic("gcm: incorrect nonce length given to GCM")
+	}
+
+	if len(ciphertext) < g.tagSize {
+		return nil, ErrOpen
+	}
+
+	tag := ciphertext[len(ciphertext)-g.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-g.tagSize]
+
+	j0 := g.initialCounterBlock(nonce)
+	if subtle.ConstantTimeCompare(g.tag(j0, additionalData, ciphertext), tag) != 1 {
+		return nil, ErrOpen
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	g.keystream(j0).XORKeyStream(plaintext, ciphertext)
+
+	return append(dst, plaintext...), nil
+}
+
+// initialCounterBlock derives J0 from the nonce.
+func (g *GCM) initialCounterBlock(nonce []byte) (j0 [16]byte) {
+	if len(nonce) == defaultNonceSize {
+		copy(j0[:], nonce)
+		j0[blockSize-1] = 1
+		return
+	}
+
+	return GHash(g.h, nil, nonce)
+}
+
+// keystream returns a CTR stream which starts at inc32(J0).
+func (g *GCM) keystream(j0 [16]byte) cipher.Stream {
+	counter := j0
+	binary.BigEndian.PutUint32(counter[12:], binary.BigEndian.Uint32(counter[12:])+1)
+
+	return ctr.NewCTRWithFormat(g.block, counter[:], ctr.BigEndian32)
+}
+
+// tag computes the (possibly truncated) authentication tag.
+func (g *GCM) tag(j0 [16]byte, additionalData, ciphertext []byte) []byte {
+	s := GHash(g.h, additionalData, ciphertext)
+
+	t := make([]byte, blockSize)
+	g.block.Encrypt(t, j0[:])
+	for i := range t {
+		t[i] ^= s[i]
+	}
+
+	return t[:g.tagSize]
+}
@@ -0,0 +1,219 @@
+package gcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+const (
+	gcmSpecKey       = "feffe9928665731c6d6a8f9467308308"
+	gcmSpecPlaintext = "d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a72" +
+		"1c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b391aafd255"
+	gcmSpecAdditionalData = "feedfacedeadbeeffeedfacedeadbeefabaddad2"
+)
+
+func TestGCM_Seal(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		type args struct {
+			key, nonce, plaintext, additionalData []byte
+		}
+		tests := []struct {
+			name           string
+			args           args
+			wantCiphertext []byte
+			wantTag        []byte
+		}{
+			// Test cases from The Galois/Counter Mode of Operation (GCM), McGrew and Viega,
+			// as used in the NIST GCM validation suite.
+			{
+				"test_case_1",
+				args{make([]byte, 16), make([]byte, 12), nil, nil},
+				[]byte{},
+				decodeHex("58e2fccefa7e3061367f1d57a4e7455a"),
+			},
+			{
+				"test_case_2",
+				args{make([]byte, 16), make([]byte, 12), make([]byte, 16), nil},
+				decodeHex("0388dace60b6a392f328c2b971b2fe78"),
+				decodeHex("ab6e47d42cec13bdf53a67b21257bddf"),
+			},
+			{
+				"test_case_3",
+				args{decodeHex(gcmSpecKey), decodeHex("cafebabefacedbaddecaf888"), decodeHex(gcmSpecPlaintext), nil},
+				decodeHex("42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e" +
+					"21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091473f5985"),
+				decodeHex("4d5c2af327cd64a62cf35abd2ba6fab4"),
+			},
+			{
+				"test_case_4",
+				args{decodeHex(gcmSpecKey), decodeHex("cafebabefacedbaddecaf888"), decodeHex(gcmSpecPlaintext[:120]), decodeHex(gcmSpecAdditionalData)},
+				decodeHex("42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e" +
+					"21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091"),
+				decodeHex("5bc94fbc3221a5db94fae95ae7121a47"),
+			},
+			{
+				"test_case_5_short_nonce",
+				args{decodeHex(gcmSpecKey), decodeHex("cafebabefacedbad"), decodeHex(gcmSpecPlaintext[:120]), decodeHex(gcmSpecAdditionalData)},
+				decodeHex("61353b4c2806934a777ff51fa22a4755699b2a714fcdc6f83766e5f97b6c7423" +
+					"73806900e49f24b22b097544d4896b424989b5e1ebac0f07c23f4598"),
+				decodeHex("3612d2e79e3b0785561be14aaca2fccb"),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				block, err := ecb.NewCipher(tt.args.key)
+				if err != nil {
+					t.Errorf("ecb.NewCipher() error = %v", err)
+					return
+				}
+
+				g, err := NewGCMWithNonceAndTagSize(block, len(tt.args.nonce), 16)
+				if err != nil {
+					t.Errorf("NewGCMWithNonceAndTagSize() error = %v", err)
+					return
+				}
+
+				sealed := g.Seal(nil, tt.args.nonce, tt.args.plaintext, tt.args.additionalData)
+				gotCiphertext, gotTag := sealed[:len(sealed)-16], sealed[len(sealed)-16:]
+				if !reflect.DeepEqual(gotCiphertext, tt.wantCiphertext) {
+					t.Errorf("GCM.Seal() ciphertext = %x, want %x", gotCiphertext, tt.wantCiphertext)
+				}
+				if !reflect.DeepEqual(gotTag, tt.wantTag) {
+					t.Errorf("GCM.Seal() tag = %x, want %x", gotTag, tt.wantTag)
+				}
+
+				gotPlaintext, err := g.Open(nil, tt.args.nonce, sealed, tt.args.additionalData)
+				if err != nil {
+					t.Errorf("GCM.Open() error = %v", err)
+					return
+				}
+				if !reflect.DeepEqual(gotPlaintext, tt.args.plaintext) {
+					t.Errorf("GCM.Open() = %x, want %x", gotPlaintext, tt.args.plaintext)
+				}
+			})
+		}
+	})
+}
+
+func TestGCM_matchesCryptoCipher(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []struct {
+			name      string
+			keySize   int
+			nonceSize int
+			tagSize   int
+		}{
+			{"aes128", 16, 12, 16},
+			{"aes256", 32, 12, 16},
+			{"long_nonce", 16, 60, 16},
+			{"tag_12", 24, 12, 12},
+			{"tag_14", 16, 12, 14},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				key := random.Bytes(tt.keySize)
+				block, _ := ecb.NewCipher(key)
+				g, err := NewGCMWithNonceAndTagSize(block, tt.nonceSize, tt.tagSize)
+				if err != nil {
+					t.Errorf("NewGCMWithNonceAndTagSize() error = %v", err)
+					return
+				}
+
+				stdBlock, _ := aes.NewCipher(key)
+				var std cipher.AEAD
+				if tt.nonceSize != 12 {
+					std, err = cipher.NewGCMWithNonceSize(stdBlock, tt.nonceSize)
+				} else {
+					std, err = cipher.NewGCMWithTagSize(stdBlock, tt.tagSize)
+				}
+				if err != nil {
+					t.Errorf("crypto/cipher error = %v", err)
+					return
+				}
+
+				for _, length := range []int{0, 1, 15, 16, 17, 100} {
+					nonce := random.Bytes(tt.nonceSize)
+					plaintext := random.Bytes(length)
+					additionalData := random.Bytes(length / 2)
+
+					got := g.Seal(nil, nonce, plaintext, additionalData)
+					want := std.Seal(nil, nonce, plaintext, additionalData)
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("GCM.Seal() = %x, want %x", got, want)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestGCM_Open(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		g, _ := NewAesGCM(random.Bytes(16))
+		nonce := random.Bytes(12)
+		sealed := g.Seal(nil, nonce, []byte("attack at dawn"), []byte("header"))
+
+		tests := []struct {
+			name           string
+			ciphertext     []byte
+			additionalData []byte
+			wantErr        bool
+		}{
+			{"genuine", sealed, []byte("header"), false},
+			{"flipped_ciphertext", flipBit(sealed, 0), []byte("header"), true},
+			{"flipped_tag", flipBit(sealed, len(sealed)-1), []byte("header"), true},
+			{"wrong_additional_data", sealed, []byte("Header"), true},
+			{"too_short", sealed[:4], []byte("header"), true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := g.Open(nil, nonce, tt.ciphertext, tt.additionalData)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GCM.Open() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func flipBit(in []byte, i int) []byte {
+	out := make([]byte, len(in))
+	copy(out, in)
+	out[i] ^= 0x01
+
+	return out
+}
+
+func TestGHash(t *testing.T) {
+	// The Galois/Counter Mode of Operation (GCM), test case 2:
+	// H = 66e94bd4ef8a2c3b884cfa59ca342b2e, GHASH(H, {}, C) = f38cbb1ad69223dcc3457ae5b6b0f885
+	var h [16]byte
+	copy(h[:], decodeHex("66e94bd4ef8a2c3b884cfa59ca342b2e"))
+
+	got := GHash(h, nil, decodeHex("0388dace60b6a392f328c2b971b2fe78"))
+	want := decodeHex("f38cbb1ad69223dcc3457ae5b6b0f885")
+	if !reflect.DeepEqual(got[:], want) {
+		t.Errorf("GHash() = %x, want %x", got, want)
+	}
+
+	g, _ := NewAesGCM(make([]byte, 16))
+	if gotH := g.H(); !reflect.DeepEqual(gotH, h) {
+		t.Errorf("GCM.H() = %x, want %x", gotH, h)
+	}
+}
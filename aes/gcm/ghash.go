@@ -0,0 +1,75 @@
+package gcm
+
+import (
+	"encoding/binary"
+)
+
+// Mul multiplies two elements of GF(2^128) using the GCM bit ordering, where
+// the first bit of the first byte is the coefficient of x^0, and the field is
+// defined by x^128 + x^7 + x^2 + x + 1.
+// NIST SP 800-38D, Algorithm 1
+func Mul(x, y [16]byte) (z [16]byte) {
+	var zHi, zLo uint64
+	vHi := binary.BigEndian.Uint64(y[:8])
+	vLo := binary.BigEndian.Uint64(y[8:])
+	xHi := binary.BigEndian.Uint64(x[:8])
+	xLo := binary.BigEndian.Uint64(x[8:])
+
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = (xHi >> uint(63-i)) & 1
+		} else {
+			bit = (xLo >> uint(127-i)) & 1
+		}
+
+		if bit == 1 {
+			zHi ^= vHi
+			zLo ^= vLo
+		}
+
+		// multiply v by x, reducing by R = 11100001 || 0^120 if needed
+		reduce := vLo & 1
+		vLo = (vLo >> 1) | (vHi << 63)
+		vHi >>= 1
+		if reduce == 1 {
+			vHi ^= 0xe1 << 56
+		}
+	}
+
+	binary.BigEndian.PutUint64(z[:8], zHi)
+	binary.BigEndian.PutUint64(z[8:], zLo)
+
+	return
+}
+
+// GHash computes GHASH with the hash subkey h over the additional data and
+// ciphertext, each zero padded to a whole number of blocks, followed by a
+// block holding their lengths in bits.
+// NIST SP 800-38D, Algorithm 2 and Algorithm 4, step 5
+func GHash(h [16]byte, additionalData, ciphertext []byte) (y [16]byte) {
+	y = ghashUpdate(h, y, additionalData)
+	y = ghashUpdate(h, y, ciphertext)
+
+	var lengths [16]byte
+	binary.BigEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.BigEndian.PutUint64(lengths[8:], uint64(len(ciphertext))*8)
+
+	return ghashUpdate(h, y, lengths[:])
+}
+
+// ghashUpdate absorbs data into y one block at a time, zero padding the last block.
+func ghashUpdate(h, y [16]byte, data []byte) [16]byte {
+	for len(data) > 0 {
+		var block [16]byte
+		n := copy(block[:], data)
+		data = data[n:]
+
+		for i := range y {
+			y[i] ^= block[i]
+		}
+		y = Mul(y, h)
+	}
+
+	return y
+}
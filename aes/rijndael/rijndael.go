@@ -0,0 +1,245 @@
+package rijndael
+
+import (
+	"fmt"
+)
+
+// BlockSize is the AES block size in bytes.
+const BlockSize = 16
+
+// State is the 4x4 byte AES state. Byte r+4c holds row r of column c, so the
+// state has the same byte order as the input and output blocks (FIPS 197, 3.4).
+type State [BlockSize]byte
+
+// RoundHook is called with the state after each round, and may modify it.
+// Round 0 is the initial AddRoundKey, and round Rounds() is the final round.
+// For decryption, rounds are numbered in the order they are applied.
+type RoundHook func(round int, state *State)
+
+// Cipher is a from-scratch implementation of AES which exposes its round keys
+// and internal state. It implements cipher.Block, so it can be used anywhere
+// crypto/aes can.
+type Cipher struct {
+	rounds      int
+	roundKeys   []State
+	encryptHook RoundHook
+	decryptHook RoundHook
+}
+
+// NewCipher expands a 16, 24, or 32 byte key and returns an AES cipher with
+// the standard number of rounds (10, 12, or 14 respectively).
+func NewCipher(key []byte) (*Cipher, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("key has length %d, which is not a valid key size for AES", len(key))
+	}
+
+	return NewCipherWithRounds(key, len(key)/4+6)
+}
+
+// NewCipherWithRounds returns an AES cipher which runs for the given number of
+// rounds, for reduced-round cryptanalysis. The key schedule is extended or cut
+// short to produce one round key per round, plus the initial key.
+func NewCipherWithRounds(key []byte, rounds int) (*Cipher, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("key has length %d, which is not a valid key size for AES", len(key))
+	}
+
+	if rounds < 1 {
+		return nil, fmt.Errorf("number of rounds must be positive, got %d", rounds)
+	}
+
+	return &Cipher{rounds: rounds, roundKeys: ExpandKey(key, rounds)}, nil
+}
+
+// BlockSize returns the AES block size.
+func (c *Cipher) BlockSize() int {
+	return BlockSize
+}
+
+// Rounds returns the number of rounds the cipher runs for.
+func (c *Cipher) Rounds() int {
+	return c.rounds
+}
+
+// RoundKeys returns a copy of the expanded key schedule, one key per round.
+func (c *Cipher) RoundKeys() []State {
+	keys := make([]State, len(c.roundKeys))
+	copy(keys, c.roundKeys)
+
+	return keys
+}
+
+// SetEncryptHook sets a function to be called after each round of encryption.
+// Pass nil to remove it.
+func (c *Cipher) SetEncryptHook(hook RoundHook) {
+	c.encryptHook = hook
+}
+
+// SetDecryptHook sets a function to be called after each round of decryption.
+// Pass nil to remove it.
+func (c *Cipher) SetDecryptHook(hook RoundHook) {
+	c.decryptHook = hook
+}
+
+// Encrypt encrypts the first block of src into dst.
+// FIPS 197, 5.1
+func (c *Cipher) Encrypt(dst, src []byte) {
+	if len(src) < BlockSize || len(dst) < BlockSize {
+		panic("rijndael: input not full block")
+	}
+
+	var state State
+	copy(state[:], src)
+
+	AddRoundKey(&state, &c.roundKeys[0])
+	c.afterRound(c.encryptHook, 0, &state)
+
+	for round := 1; round <= c.rounds; round++ {
+		SubBytes(&state)
+		ShiftRows(&state)
+		// the final round has no MixColumns
+		if round < c.rounds {
+			MixColumns(&state)
+		}
+		AddRoundKey(&state, &c.roundKeys[round])
+		c.afterRound(c.encryptHook, round, &state)
+	}
+
+	copy(dst, state[:])
+}
+
+// Decrypt decrypts the first block of src into dst.
+// FIPS 197, 5.3
+func (c *Cipher) Decrypt(dst, src []byte) {
+	if len(src) < BlockSize || len(dst) < BlockSize {
+		panic("rijndael: input not full block")
+	}
+
+	var state State
+	copy(state[:], src)
+
+	AddRoundKey(&state, &c.roundKeys[c.rounds])
+	c.afterRound(c.decryptHook, 0, &state)
+
+	for round := 1; round <= c.rounds; round++ {
+		InvShiftRows(&state)
+		InvSubBytes(&state)
+		AddRoundKey(&state, &c.roundKeys[c.rounds-round])
+		if round < c.rounds {
+			InvMixColumns(&state)
+		}
+		c.afterRound(c.decryptHook, round, &state)
+	}
+
+	copy(dst, state[:])
+}
+
+func (c *Cipher) afterRound(hook RoundHook, round int, state *State) {
+	if hook != nil {
+		hook(round, state)
+	}
+}
+
+// ExpandKey runs the AES key schedule to produce rounds+1 round keys.
+// FIPS 197, 5.2
+func ExpandKey(key []byte, rounds int) []State {
+	nk := len(key) / 4
+	words := make([][4]byte, 4*(rounds+1))
+
+	for i := 0; i < nk && i < len(words); i++ {
+		copy(words[i][:], key[4*i:4*i+4])
+	}
+
+	rcon := byte(0x01)
+	for i := nk; i < len(words); i++ {
+		temp := words[i-1]
+		if i%nk == 0 {
+			// RotWord, SubWord, and XOR with Rcon
+			temp = [4]byte{sbox[temp[1]] ^ rcon, sbox[temp[2]], sbox[temp[3]], sbox[temp[0]]}
+			rcon = xtime(rcon)
+		} else if nk > 6 && i%nk == 4 {
+			temp = [4]byte{sbox[temp[0]], sbox[temp[1]], sbox[temp[2]], sbox[temp[3]]}
+		}
+
+		for j := range temp {
+			words[i][j] = words[i-nk][j] ^ temp[j]
+		}
+	}
+
+	roundKeys := make([]State, rounds+1)
+	for i := range roundKeys {
+		for j := 0; j < 4; j++ {
+			copy(roundKeys[i][4*j:4*j+4], words[4*i+j][:])
+		}
+	}
+
+	return roundKeys
+}
+
+// AddRoundKey XORs the round key into the state.
+func AddRoundKey(state, roundKey *State) {
+	for i := range state {
+		state[i] ^= roundKey[i]
+	}
+}
+
+// SubBytes replaces each byte of the state with its S-box entry.
+func SubBytes(state *State) {
+	for i := range state {
+		state[i] = sbox[state[i]]
+	}
+}
+
+// InvSubBytes reverses SubBytes.
+func InvSubBytes(state *State) {
+	for i := range state {
+		state[i] = invSbox[state[i]]
+	}
+}
+
+// ShiftRows cyclically shifts row r of the state left by r places.
+func ShiftRows(state *State) {
+	old := *state
+	for r := 1; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			state[r+4*c] = old[r+4*((c+r)%4)]
+		}
+	}
+}
+
+// InvShiftRows reverses ShiftRows.
+func InvShiftRows(state *State) {
+	old := *state
+	for r := 1; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			state[r+4*((c+r)%4)] = old[r+4*c]
+		}
+	}
+}
+
+// MixColumns multiplies each column of the state by the fixed polynomial
+// {03}x^3 + {01}x^2 + {01}x + {02}.
+func MixColumns(state *State) {
+	for c := 0; c < 4; c++ {
+		a0, a1, a2, a3 := state[4*c], state[4*c+1], state[4*c+2], state[4*c+3]
+		state[4*c] = mul(a0, 2) ^ mul(a1, 3) ^ a2 ^ a3
+		state[4*c+1] = a0 ^ mul(a1, 2) ^ mul(a2, 3) ^ a3
+		state[4*c+2] = a0 ^ a1 ^ mul(a2, 2) ^ mul(a3, 3)
+		state[4*c+3] = mul(a0, 3) ^ a1 ^ a2 ^ mul(a3, 2)
+	}
+}
+
+// InvMixColumns reverses MixColumns.
+func InvMixColumns(state *State) {
+	for c := 0; c < 4; c++ {
+		a0, a1, a2, a3 := state[4*c], state[4*c+1], state[4*c+2], state[4*c+3]
+		state[4*c] = mul(a0, 0x0e) ^ mul(a1, 0x0b) ^ mul(a2, 0x0d) ^ mul(a3, 0x09)
+		state[4*c+1] = mul(a0, 0x09) ^ mul(a1, 0x0e) ^ mul(a2, 0x0b) ^ mul(a3, 0x0d)
+		state[4*c+2] = mul(a0, 0x0d) ^ mul(a1, 0x09) ^ mul(a2, 0x0e) ^ mul(a3, 0x0b)
+		state[4*c+3] = mul(a0, 0x0b) ^ mul(a1, 0x0d) ^ mul(a2, 0x09) ^ mul(a3, 0x0e)
+	}
+}
@@ -0,0 +1,191 @@
+package rijndael
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func TestCipher_Encrypt(t *testing.T) {
+	tests := []struct {
+		name           string
+		key            []byte
+		plaintext      []byte
+		wantCiphertext []byte
+	}{
+		// FIPS 197, Appendix C
+		{
+			"fips_197_c_1_aes128",
+			decodeHex("000102030405060708090a0b0c0d0e0f"),
+			decodeHex("00112233445566778899aabbccddeeff"),
+			decodeHex("69c4e0d86a7b0430d8cdb78070b4c55a"),
+		},
+		{
+			"fips_197_c_2_aes192",
+			decodeHex("000102030405060708090a0b0c0d0e0f1011121314151617"),
+			decodeHex("00112233445566778899aabbccddeeff"),
+			decodeHex("dda97ca4864cdfe06eaf70a0ec0d7191"),
+		},
+		{
+			"fips_197_c_3_aes256",
+			decodeHex("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"),
+			decodeHex("00112233445566778899aabbccddeeff"),
+			decodeHex("8ea2b7ca516745bfeafc49904b496089"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCipher(tt.key)
+			if err != nil {
+				t.Errorf("NewCipher() error = %v", err)
+				return
+			}
+
+			gotCiphertext := make([]byte, BlockSize)
+			c.Encrypt(gotCiphertext, tt.plaintext)
+			if !reflect.DeepEqual(gotCiphertext, tt.wantCiphertext) {
+				t.Errorf("Cipher.Encrypt() = %x, want %x", gotCiphertext, tt.wantCiphertext)
+			}
+
+			gotPlaintext := make([]byte, BlockSize)
+			c.Decrypt(gotPlaintext, gotCiphertext)
+			if !reflect.DeepEqual(gotPlaintext, tt.plaintext) {
+				t.Errorf("Cipher.Decrypt() = %x, want %x", gotPlaintext, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestCipher_matchesCryptoAes(t *testing.T) {
+	for _, keySize := range []int{16, 24, 32} {
+		key := random.Bytes(keySize)
+		c, err := NewCipher(key)
+		if err != nil {
+			t.Errorf("NewCipher() error = %v", err)
+			return
+		}
+		std, _ := aes.NewCipher(key)
+
+		for i := 0; i < 100; i++ {
+			plaintext := random.Bytes(BlockSize)
+			got, want := make([]byte, BlockSize), make([]byte, BlockSize)
+			c.Encrypt(got, plaintext)
+			std.Encrypt(want, plaintext)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Cipher.Encrypt() = %x, want %x", got, want)
+				return
+			}
+		}
+	}
+}
+
+func TestNewCipherWithRounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		rounds  int
+		wantErr bool
+	}{
+		{"one_round", random.Bytes(16), 1, false},
+		{"four_rounds", random.Bytes(24), 4, false},
+		{"extra_rounds", random.Bytes(32), 20, false},
+		{"zero_rounds", random.Bytes(16), 0, true},
+		{"bad_key", random.Bytes(17), 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCipherWithRounds(tt.key, tt.rounds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCipherWithRounds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			plaintext := random.Bytes(BlockSize)
+			ciphertext, decrypted := make([]byte, BlockSize), make([]byte, BlockSize)
+			c.Encrypt(ciphertext, plaintext)
+			c.Decrypt(decrypted, ciphertext)
+			if !reflect.DeepEqual(decrypted, plaintext) {
+				t.Errorf("Cipher.Decrypt() = %x, want %x", decrypted, plaintext)
+			}
+		})
+	}
+}
+
+func TestCipher_SetEncryptHook(t *testing.T) {
+	c, _ := NewCipher(decodeHex("000102030405060708090a0b0c0d0e0f"))
+
+	// FIPS 197, Appendix C.1: round[1].start and round[2].start
+	wantStates := map[int][]byte{
+		0: decodeHex("00102030405060708090a0b0c0d0e0f0"),
+		1: decodeHex("89d810e8855ace682d1843d8cb128fe4"),
+	}
+
+	var rounds []int
+	c.SetEncryptHook(func(round int, state *State) {
+		rounds = append(rounds, round)
+		if want, ok := wantStates[round]; ok && !reflect.DeepEqual(state[:], want) {
+			t.Errorf("state after round %d = %x, want %x", round, state[:], want)
+		}
+	})
+
+	c.Encrypt(make([]byte, BlockSize), decodeHex("00112233445566778899aabbccddeeff"))
+
+	if !reflect.DeepEqual(rounds, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("hook called for rounds %v", rounds)
+	}
+}
+
+func TestCipher_faultInjection(t *testing.T) {
+	tests := []struct {
+		name      string
+		round     int
+		wantDiffs int
+	}{
+		// a fault before the final round (which has no MixColumns) only touches one byte
+		{"before_final_round", 9, 1},
+		// one MixColumns spreads the fault over a column
+		{"before_penultimate_round", 8, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := NewCipher(random.Bytes(16))
+			plaintext := random.Bytes(BlockSize)
+
+			good := make([]byte, BlockSize)
+			c.Encrypt(good, plaintext)
+
+			c.SetEncryptHook(func(round int, state *State) {
+				if round == tt.round {
+					state[0] ^= 0x01
+				}
+			})
+			faulty := make([]byte, BlockSize)
+			c.Encrypt(faulty, plaintext)
+
+			diffs := 0
+			for i := range good {
+				if good[i] != faulty[i] {
+					diffs++
+				}
+			}
+			if diffs != tt.wantDiffs {
+				t.Errorf("faulty ciphertext differs in %d bytes, want %d", diffs, tt.wantDiffs)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package rijndael
+
+// sbox and invSbox are generated at init from the field arithmetic rather
+// than copied from a table.
+var sbox, invSbox [256]byte
+
+func init() {
+	for i := 0; i < 256; i++ {
+		b := inverse(byte(i))
+		// affine transformation, FIPS 197, 5.1.1
+		s := b ^ rotl(b, 1) ^ rotl(b, 2) ^ rotl(b, 3) ^ rotl(b, 4) ^ 0x63
+		sbox[i] = s
+		invSbox[s] = byte(i)
+	}
+}
+
+// xtime multiplies by x (i.e. {02}) in GF(2^8), modulo x^8 + x^4 + x^3 + x + 1.
+func xtime(a byte) byte {
+	if a&0x80 != 0 {
+		return (a << 1) ^ 0x1b
+	}
+
+	return a << 1
+}
+
+// mul multiplies two elements of GF(2^8).
+func mul(a, b byte) (product byte) {
+	for b > 0 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		a = xtime(a)
+		b >>= 1
+	}
+
+	return
+}
+
+// inverse returns the multiplicative inverse in GF(2^8), with 0 mapped to 0.
+// a^254 = a^-1 since the multiplicative group has order 255.
+func inverse(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = mul(result, a)
+	}
+
+	if a == 0 {
+		return 0
+	}
+
+	return result
+}
+
+func rotl(b byte, n uint) byte {
+	return (b << n) | (b >> (8 - n))
+}
@@ -3,6 +3,7 @@ package attacks
 import (
 	"bytes"
     "encoding/binary"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/random/MT19937"
 	"runtime"
 	"sync"
@@ -74,12 +75,15 @@ func undoLeft(y, x uint32, d int) uint32 {
 // BreakMT19937CtrOracle recovers the cipher's key using a known plaintext attack.
 // for Cryptopals Set 3, Challenge 24
 // https://cryptopals.com/sets/3/challenges/24
-func BreakMT19937CtrOracle(oracle MT19937.CtrOracle) uint16 {
+func BreakMT19937CtrOracle(oracle oracle.EncryptionOracle) (key uint16, err error) {
 	w := make([]byte, 4)
     binary.LittleEndian.PutUint32(w, 0xAAAAAAAA)
 	plaintext := bytes.Repeat(w, 4)
 
-	ciphertext := oracle.Encrypt(plaintext)
+	ciphertext, err := oracle.Encrypt(plaintext)
+	if err != nil {
+		return
+	}
 
 	cores := runtime.NumCPU()
     keyChan := make(chan uint32, 1<<16)
@@ -96,13 +100,13 @@ func BreakMT19937CtrOracle(oracle MT19937.CtrOracle) uint16 {
         successChans[i] = tryKeys(keyChan, cancelChan, plaintext, ciphertext)
     }
 
-    key := <-merge(successChans, cancelChan)
+    found := <-merge(successChans, cancelChan)
 
     for i := 0; i < cores; i++ {
         cancelChan <- 0
     }
 
-	return uint16(key)
+	return uint16(found), nil
 }
 
 // RecoverTimedSeed recovers the time used as a seed for a Mersenne Twister PRNG
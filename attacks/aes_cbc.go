@@ -1,7 +1,10 @@
 package attacks
 
 import (
+	"fmt"
+
 	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 	"github.com/adavidalbertson/cryptopals/xor"
 
@@ -11,7 +14,7 @@ import (
 // AesCbcOracleBreak creates a user token with admin parameter set to true.
 // Cryptopals Set 2, Challenge 16
 // https://cryptopals.com/sets/2/challenges/16
-func AesCbcOracleBreak(oracle cbc.AesCbcOracle) (token []byte, err error) {
+func AesCbcOracleBreak(oracle oracle.EncryptionOracle) (token []byte, err error) {
 	blockSize := 16
 	// luckily the prefix is exactly two blocks long
 	empty := strings.Repeat("0", blockSize+5)
@@ -19,7 +22,7 @@ func AesCbcOracleBreak(oracle cbc.AesCbcOracle) (token []byte, err error) {
 	plaintext, altered := flipChars(empty + plaintext)
 
 	plaintext = empty + ":;admin<=true"
-	token, err = oracle.Encrypt(plaintext)
+	token, err = oracle.Encrypt([]byte(plaintext))
 	if err != nil {
 		return make([]byte, 0), err
 	}
@@ -46,16 +49,12 @@ func flipChars(in string) (s string, indices []int) {
 	}
 }
 
-// PaddingOracleAttack determines the oracle's plaintext.
+// PaddingOracleAttack decrypts a PKCS#7 padded CBC ciphertext using only
+// a validator that reports whether a ciphertext's padding is correct.
 // Cryptopals Set 3, Challenge 17
 // https://cryptopals.com/sets/3/challenges/17
-func PaddingOracleAttack(oracle cbc.PaddingOracle) ([]byte, error) {
+func PaddingOracleAttack(validator oracle.PaddingValidator, ciphertext, iv []byte) ([]byte, error) {
 	blockSize := 16
-	ciphertext, iv, err := oracle.Encrypt()
-	if err != nil {
-		return nil, err
-	}
-
 	plaintext := make([]byte, len(ciphertext))
 
 	var left []byte
@@ -91,7 +90,11 @@ func PaddingOracleAttack(oracle cbc.PaddingOracle) ([]byte, error) {
 					trialIV := make([]byte, blockSize)
 					copy(trialIV, alteredBlock)
 					trialIV[i] = trialIV[i] ^ byte(k)
-					if oracle.Validate(ciphertext[block+blockSize:block+2*blockSize], trialIV) {
+					valid, err := validator.Validate(ciphertext[block+blockSize:block+2*blockSize], trialIV)
+					if err != nil {
+						return nil, err
+					}
+					if valid {
 						plaintext[block+blockSize+i] = byte(k)
 						break
 					}
@@ -101,7 +104,11 @@ func PaddingOracleAttack(oracle cbc.PaddingOracle) ([]byte, error) {
 
 					trialCiphertext := append(left, trialBlock...)
 					trialCiphertext = append(trialCiphertext, ciphertext[block+blockSize:block+2*blockSize]...)
-					if oracle.Validate(trialCiphertext, iv) {
+					valid, err := validator.Validate(trialCiphertext, iv)
+					if err != nil {
+						return nil, err
+					}
+					if valid {
 						plaintext[block+blockSize+i] = byte(k)
 						// can't break here because ciphertext block may contain padding
 					}
@@ -112,3 +119,39 @@ func PaddingOracleAttack(oracle cbc.PaddingOracle) ([]byte, error) {
 	plaintext, _ = padding.Pkcs7Unpad(plaintext)
 	return plaintext, nil
 }
+
+// KeyAsIvAttack recovers the key of a CBC oracle that uses its key as the
+// iv, from the error it returns for plaintext with high-ASCII bytes.
+// For a token C1 || C2 || C3 || ..., decrypting C1 || 0 || C1 gives
+// P1 = D(C1) ^ key and P3 = D(C1) ^ 0, so P1 ^ P3 = key.
+// Cryptopals Set 4, Challenge 27
+// https://cryptopals.com/sets/4/challenges/27
+func KeyAsIvAttack(encrypter oracle.EncryptionOracle, checker oracle.AdminChecker) (key []byte, err error) {
+	blockSize := 16
+
+	token, err := encrypter.Encrypt([]byte{})
+	if err != nil {
+		return
+	}
+	if len(token) < 3*blockSize {
+		return nil, fmt.Errorf("Token too short: %d bytes", len(token))
+	}
+
+	// keep the last two blocks so the padding stays valid
+	c1 := token[:blockSize]
+	attack := append([]byte{}, c1...)
+	attack = append(attack, make([]byte, blockSize)...)
+	attack = append(attack, c1...)
+	attack = append(attack, token[len(token)-2*blockSize:]...)
+
+	_, err = checker.Decrypt(attack)
+	plaintext, ok := cbc.PlaintextFromError(err)
+	if !ok {
+		if err == nil {
+			err = fmt.Errorf("Oracle accepted the modified token")
+		}
+		return nil, err
+	}
+
+	return xor.Xor(plaintext[:blockSize], plaintext[2*blockSize:3*blockSize])
+}
@@ -1,25 +1,105 @@
 package attacks
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/random"
 )
 
 func TestAesCbcOracleBreak(t *testing.T) {
-	t.Run("challenge_16", func(t *testing.T) {
-		oracle := cbc.NewAesCbcOracle()
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		t.Run("challenge_16", func(t *testing.T) {
+			oracle := cbc.NewAesCbcOracle()
 
-		gotToken, err := AesCbcOracleBreak(oracle)
-		isAdmin, err := oracle.Decrypt(gotToken)
+			gotToken, err := AesCbcOracleBreak(oracle)
+			isAdmin, err := oracle.Decrypt(gotToken)
 
+			if err != nil {
+				t.Errorf("AesCbcOracleBreak() error = %v", err)
+				return
+			}
+
+			if !isAdmin {
+				t.Errorf("NewAesCbcOracle.Decrypt(AesCbcOracleBreak()) = %v, want %v", gotToken, false)
+			}
+		})
+	})
+}
+
+func TestKeyAsIvAttack(t *testing.T) {
+	t.Run("challenge_27", func(t *testing.T) {
+		oracle := cbc.NewKeyAsIvOracle()
+
+		key, err := KeyAsIvAttack(oracle, oracle)
 		if err != nil {
-			t.Errorf("AesCbcOracleBreak() error = %v", err)
+			t.Errorf("KeyAsIvAttack() error = %v", err)
 			return
 		}
 
+		// with the key, any token can be forged
+		plaintext, _ := padding.Pkcs7([]byte("userdata=x;admin=true"), 16)
+		token, _ := cbc.Encrypt(plaintext, key, key)
+		isAdmin, err := oracle.Decrypt(token)
+		if err != nil {
+			t.Errorf("AesCbcOracle.Decrypt() error = %v", err)
+			return
+		}
 		if !isAdmin {
-			t.Errorf("NewAesCbcOracle.Decrypt(AesCbcOracleBreak()) = %v, want %v", gotToken, false)
+			t.Errorf("AesCbcOracle.Decrypt() with key from KeyAsIvAttack() = %v, want %v", isAdmin, true)
+		}
+	})
+}
+
+// mockPaddingValidator checks padding with a key known to the test, standing
+// in for a remote service.
+type mockPaddingValidator struct {
+	key     []byte
+	queries int
+}
+
+func (v *mockPaddingValidator) Validate(ciphertext, iv []byte) (bool, error) {
+	v.queries++
+	decrypted, err := cbc.Decrypt(ciphertext, v.key, iv)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = padding.Pkcs7Unpad(decrypted)
+	return err == nil, nil
+}
+
+func TestPaddingOracleAttack(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []struct {
+			name      string
+			plaintext []byte
+		}{
+			{"one_block", []byte("YELLOW SUBMARINE")},
+			{"partial_block", []byte("I'm back and I'm ringin' the bell")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				validator := &mockPaddingValidator{key: random.Bytes(16)}
+				iv := random.Bytes(16)
+				padded, _ := padding.Pkcs7(tt.plaintext, 16)
+				ciphertext, _ := cbc.Encrypt(padded, validator.key, iv)
+
+				got, err := PaddingOracleAttack(validator, ciphertext, iv)
+				if err != nil {
+					t.Errorf("PaddingOracleAttack() error = %v", err)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.plaintext) {
+					t.Errorf("PaddingOracleAttack() = %q, want %q", got, tt.plaintext)
+				}
+				if validator.queries == 0 {
+					t.Errorf("PaddingOracleAttack() made no queries")
+				}
+			})
 		}
 	})
 }
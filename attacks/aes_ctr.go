@@ -2,7 +2,7 @@ package attacks
 
 import (
 	"fmt"
-	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/xor"
 )
 
@@ -10,19 +10,18 @@ import (
 // ciphertext. This reveals the keyStream.
 // Cryptopals Set 4, Challenge 25
 // https://cryptopals.com/sets/4/challenges/25
-func BreakCtrEdit(cipher ctr.AesCtrCipher, ciphertext []byte) (plaintext []byte, err error) {
+func BreakCtrEdit(cipher oracle.EditOracle, ciphertext []byte) (plaintext []byte, err error) {
 	plaintext = make([]byte, 0)
 	fullBlockSize := 16
 	for i := 0; i < len(ciphertext); i += fullBlockSize {
 		blockSize := fullBlockSize
-		if i+blockSize >= len(ciphertext) {
+		if i+blockSize > len(ciphertext) {
 			blockSize = len(ciphertext) % blockSize
 		}
 
 		insertBlock := make([]byte, blockSize)
 		edited, err := cipher.Edit(ciphertext, insertBlock, i/fullBlockSize)
 		if err != nil {
-			fmt.Println(err)
 			return make([]byte, 0), err
 		}
 
@@ -38,3 +37,49 @@ func BreakCtrEdit(cipher ctr.AesCtrCipher, ciphertext []byte) (plaintext []byte,
 	}
 	return
 }
+
+// AesCtrOracleBreak creates a user token with admin parameter set to true.
+// CTR ciphertext is the plaintext XORed with the keystream, so flipping a
+// ciphertext bit flips the same plaintext bit: the attack encrypts harmless
+// userdata and XORs ";admin=true" over it in place.
+// Cryptopals Set 4, Challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+func AesCtrOracleBreak(oracle oracle.EncryptionOracle) (token []byte, err error) {
+	target := []byte(";admin=true")
+
+	// the userdata starts at the first byte that differs between two tokens
+	a, err := oracle.Encrypt([]byte("A"))
+	if err != nil {
+		return
+	}
+	b, err := oracle.Encrypt([]byte("B"))
+	if err != nil {
+		return
+	}
+	prefixLength := 0
+	for prefixLength < len(a) && a[prefixLength] == b[prefixLength] {
+		prefixLength++
+	}
+
+	filler := make([]byte, len(target))
+	for i := range filler {
+		filler[i] = 'A'
+	}
+	token, err = oracle.Encrypt(filler)
+	if err != nil {
+		return
+	}
+	if prefixLength+len(target) > len(token) {
+		return nil, fmt.Errorf("Could not find the userdata in the token")
+	}
+
+	delta, err := xor.Xor(filler, target)
+	if err != nil {
+		return
+	}
+	for i, d := range delta {
+		token[prefixLength+i] ^= d
+	}
+
+	return
+}
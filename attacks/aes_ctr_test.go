@@ -0,0 +1,62 @@
+package attacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func TestBreakCtrEdit(t *testing.T) {
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		tests := []struct {
+			name      string
+			plaintext []byte
+		}{
+			{"one_block", []byte("YELLOW SUBMARINE")},
+			{"partial_block", []byte("Burning 'em, if you ain't quick and nimble")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				cipher, err := ctr.NewAesCtrCipher(random.Bytes(16), random.Bytes(8))
+				if err != nil {
+					t.Errorf("NewAesCtrCipher() error = %v", err)
+					return
+				}
+				ciphertext, _ := cipher.Encrypt(tt.plaintext)
+
+				got, err := BreakCtrEdit(&cipher, ciphertext)
+				if err != nil {
+					t.Errorf("BreakCtrEdit() error = %v", err)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.plaintext) {
+					t.Errorf("BreakCtrEdit() = %q, want %q", got, tt.plaintext)
+				}
+			})
+		}
+	})
+}
+
+func TestAesCtrOracleBreak(t *testing.T) {
+	t.Run("challenge_26", func(t *testing.T) {
+		oracle := ctr.NewAesCtrOracle()
+
+		gotToken, err := AesCtrOracleBreak(oracle)
+		if err != nil {
+			t.Errorf("AesCtrOracleBreak() error = %v", err)
+			return
+		}
+
+		isAdmin, err := oracle.Decrypt(gotToken)
+		if err != nil {
+			t.Errorf("AesCtrOracle.Decrypt() error = %v", err)
+			return
+		}
+		if !isAdmin {
+			t.Errorf("AesCtrOracle.Decrypt(AesCtrOracleBreak()) = %v, want %v", isAdmin, true)
+		}
+	})
+}
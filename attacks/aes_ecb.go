@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
-	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/padding"
 )
 
@@ -26,11 +26,11 @@ func AesEcbDetect(inBytes []byte, blockSize int) bool {
 	return matches > 0
 }
 
-// AesEcbOracleDetectBlockSize detects the blocksize of the cipher used in AesEcbOracle.
+// AesEcbOracleDetectBlockSize detects the blocksize of the cipher used by an ECB encryption oracle.
 // Returns 0 if ECB is not detected
 // Cryptopals Set 2, Challenge 12
 // https://cryptopals.com/sets/2/challenges/12
-func AesEcbOracleDetectBlockSize(oracle ecb.AesEcbOracle) (blockSize int, err error) {
+func AesEcbOracleDetectBlockSize(oracle oracle.EncryptionOracle) (blockSize int, err error) {
 	ct1, err := oracle.Encrypt(make([]byte, 0))
 	if err != nil {
 		return
@@ -38,9 +38,10 @@ func AesEcbOracleDetectBlockSize(oracle ecb.AesEcbOracle) (blockSize int, err er
 
 	for i := 0; i <= 2048; i++ {
 		plaintext := bytes.Repeat([]byte("A"), i)
-		ct2, err := oracle.Encrypt(plaintext)
+		var ct2 []byte
+		ct2, err = oracle.Encrypt(plaintext)
 		if err != nil {
-			break
+			return
 		}
 
 		if len(ct2) > len(ct1) {
@@ -52,7 +53,7 @@ func AesEcbOracleDetectBlockSize(oracle ecb.AesEcbOracle) (blockSize int, err er
 }
 
 // getBlankBlock returns the ciphertext block corresponding to a zero plaintext.
-func getBlankBlock(oracle ecb.AesEcbOracle, blockSize int) (blankBlock []byte, err error) {
+func getBlankBlock(oracle oracle.EncryptionOracle, blockSize int) (blankBlock []byte, err error) {
 	plaintext := make([]byte, 3*blockSize)
 	ciphertext, err := oracle.Encrypt(plaintext)
 	if err != nil {
@@ -67,11 +68,12 @@ func getBlankBlock(oracle ecb.AesEcbOracle, blockSize int) (blankBlock []byte, e
 	return make([]byte, blockSize), fmt.Errorf("Could not make blank block")
 }
 
-// AesEcbOracleBreak recovers unknown bytes from AesEcbOracle one byte at a time.
+// AesEcbOracleBreak recovers the unknown suffix an ECB encryption oracle
+// appends to its input, one byte at a time.
 // Adapted to accommodate prefix for Challenge 14.
 // Cryptopals Set 2, Challenge 14
 // https://cryptopals.com/sets/2/challenges/14
-func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
+func AesEcbOracleBreak(oracle oracle.EncryptionOracle) (plaintext []byte, err error) {
 	blockSize, err := AesEcbOracleDetectBlockSize(oracle)
 	if err != nil {
 		return
@@ -86,9 +88,10 @@ func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
 	startBlock := 0
 	finishedLength := 0
 	for i := 0; i < blockSize; i++ {
-		ciphertext, err := oracle.Encrypt(make([]byte, blockSize+i))
+		var ciphertext []byte
+		ciphertext, err = oracle.Encrypt(make([]byte, blockSize+i))
 		if err != nil {
-			break
+			return
 		}
 		for j := 0; j <= len(ciphertext)-blockSize; j += blockSize {
 			if bytes.Equal(ciphertext[j:j+blockSize], blankBlock) {
@@ -101,9 +104,6 @@ func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
 			break
 		}
 	}
-	if err != nil {
-		return
-	}
 
 	var recovered []byte
 	// Recover the i-th byte of the unknown suffix in oracle.
@@ -112,9 +112,10 @@ func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
 		// We're recovering the (i % blockSize)-th byte of the block-th block.
 		block := (i / blockSize)
 		dummy := bytes.Repeat([]byte("A"), fill+blockSize-1-(i%blockSize))
-		ciphertext, err := oracle.Encrypt(dummy)
+		var ciphertext []byte
+		ciphertext, err = oracle.Encrypt(dummy)
 		if err != nil {
-			break
+			return
 		}
 
 		matchingBlock := ciphertext[block*blockSize+startBlock : (block+1)*blockSize+startBlock]
@@ -122,9 +123,10 @@ func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
 		for j := 0; j < 256; j++ {
 			plaintext := append(dummy, recovered...)
 			plaintext = append(plaintext, byte(j))
-			dictEntry, err := oracle.Encrypt(plaintext)
+			var dictEntry []byte
+			dictEntry, err = oracle.Encrypt(plaintext)
 			if err != nil {
-				break
+				return nil, err
 			}
 
 			if bytes.Equal(matchingBlock, dictEntry[block*blockSize+startBlock:(block+1)*blockSize+startBlock]) {
@@ -137,9 +139,6 @@ func AesEcbOracleBreak(oracle ecb.AesEcbOracle) (plaintext []byte, err error) {
 			return padding.Pkcs7Unpad(recovered)
 		}
 	}
-	if err != nil {
-		return
-	}
 
 	return padding.Pkcs7Unpad(recovered)
 }
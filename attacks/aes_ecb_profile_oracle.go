@@ -5,18 +5,13 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/oracle"
 )
 
-type profileMaker interface {
-	ProfileFor(email string) (token []byte, err error)
-	DecryptProfile(ciphertext []byte) (profile ecb.UserProfile, err error)
-}
-
 // ProfileOracleDetectBlockSize detects the block size of the cipher used to encrypt user tokens.
 // Cryptopals Set 2, Challenge 13
 // https://cryptopals.com/sets/2/challenges/13
-func ProfileOracleDetectBlockSize(oracle profileMaker) (blockSize int, err error) {
+func ProfileOracleDetectBlockSize(oracle oracle.ProfileOracle) (blockSize int, err error) {
 	ciphertext, err := oracle.ProfileFor(strings.Repeat("A", 2048))
 	if err != nil {
 		return
@@ -38,7 +33,7 @@ func ProfileOracleDetectBlockSize(oracle profileMaker) (blockSize int, err error
 // ProfileSpoofAdmin creates a token with the role "admin".
 // Cryptopals Set 2, Challenge 13
 // https://cryptopals.com/sets/2/challenges/13
-func ProfileSpoofAdmin(oracle profileMaker) (ciphertext []byte, err error) {
+func ProfileSpoofAdmin(oracle oracle.ProfileOracle) (ciphertext []byte, err error) {
 	blockSize, err := ProfileOracleDetectBlockSize(oracle)
 	if err != nil {
 		return
@@ -78,7 +73,7 @@ func ProfileSpoofAdmin(oracle profileMaker) (ciphertext []byte, err error) {
 // makeFinalBlock creates a token block consisting of only the specified string.
 // Cryptopals Set 2, Challenge 13
 // https://cryptopals.com/sets/2/challenges/13
-func makeFinalBlock(oracle profileMaker, param string, blockSize int) (finalBlock []byte, err error) {
+func makeFinalBlock(oracle oracle.ProfileOracle, param string, blockSize int) (finalBlock []byte, err error) {
 	desiredBlock := param
 	if len(desiredBlock)%blockSize != 0 {
 		desiredBlock += strings.Repeat(" ", blockSize-(len(param)%blockSize))
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/random"
 )
 
@@ -19,7 +20,7 @@ func (pm randomOracle) DecryptProfile(ciphertext []byte) (profile ecb.UserProfil
 
 func TestProfileOracleDetectBlockSize(t *testing.T) {
 	type args struct {
-		oracle profileMaker
+		oracle oracle.ProfileOracle
 	}
 	tests := []struct {
 		name          string
@@ -56,7 +57,7 @@ func TestProfileOracleDetectBlockSize(t *testing.T) {
 
 func TestProfileSpoofAdmin(t *testing.T) {
 	type args struct {
-		oracle profileMaker
+		oracle ecb.ProfileMaker
 	}
 	tests := []struct {
 		name     string
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/aes/ecb/ecbtest"
 )
 
 func TestAesEcbDetect(t *testing.T) {
@@ -111,49 +112,51 @@ func TestAesEcbOracleDetectBlockSize(t *testing.T) {
 }
 
 func TestAesEcbOracleBreak(t *testing.T) {
-	type args struct {
-		oracle ecb.AesEcbOracle
-	}
-	type testCase struct {
-		name          string
-		args          args
-		wantPlaintext []byte
-		wantErr       bool
-	}
-
-	newTestCase := func(name, suffixBase64 string, addPrefix, wantErr bool) (tc testCase) {
-		var err error
-		tc.name = name
-		suffix, err := base64.StdEncoding.DecodeString(suffixBase64)
-		if err != nil {
-			panic(err)
+	ecbtest.ForEachBackend(t, func(t *testing.T) {
+		type args struct {
+			oracle ecb.AesEcbOracle
 		}
-
-		tc.args.oracle, err = ecb.NewAesEcbOracle(suffix, addPrefix)
-		if err != nil {
-			panic(err)
+		type testCase struct {
+			name          string
+			args          args
+			wantPlaintext []byte
+			wantErr       bool
 		}
 
-		tc.wantPlaintext = suffix
-		tc.wantErr = wantErr
-
-		return
-	}
-
-	tests := []testCase{
-		newTestCase("challenge_12", "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK", false, false),
-		newTestCase("challenge_14", "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK", true, false),
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			gotPlaintext, err := AesEcbOracleBreak(tt.args.oracle)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("AesEcbOracleBreak() error = %v, wantErr %v", err, tt.wantErr)
-				return
+		newTestCase := func(name, suffixBase64 string, addPrefix, wantErr bool) (tc testCase) {
+			var err error
+			tc.name = name
+			suffix, err := base64.StdEncoding.DecodeString(suffixBase64)
+			if err != nil {
+				panic(err)
 			}
-			if !reflect.DeepEqual(gotPlaintext, tt.wantPlaintext) {
-				t.Errorf("AesEcbOracleBreak() = %v, want %v", gotPlaintext, tt.wantPlaintext)
+
+			tc.args.oracle, err = ecb.NewAesEcbOracle(suffix, addPrefix)
+			if err != nil {
+				panic(err)
 			}
-		})
-	}
+
+			tc.wantPlaintext = suffix
+			tc.wantErr = wantErr
+
+			return
+		}
+
+		tests := []testCase{
+			newTestCase("challenge_12", "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK", false, false),
+			newTestCase("challenge_14", "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK", true, false),
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				gotPlaintext, err := AesEcbOracleBreak(tt.args.oracle)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("AesEcbOracleBreak() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(gotPlaintext, tt.wantPlaintext) {
+					t.Errorf("AesEcbOracleBreak() = %v, want %v", gotPlaintext, tt.wantPlaintext)
+				}
+			})
+		}
+	})
 }
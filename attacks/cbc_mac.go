@@ -0,0 +1,163 @@
+package attacks
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/cbcmac"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/xor"
+)
+
+// CbcMacIvForgery rewrites the sender of a signed single-transfer request,
+// message || iv || mac, to the victim account. The sender is in the first
+// block, which CBC XORs with the iv before encrypting, so flipping the same
+// bits of the iv leaves the MAC unchanged.
+// The victim's account number must be as long as the original sender's.
+// Cryptopals Set 7, Challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+func CbcMacIvForgery(request []byte, victim int) (forged []byte, err error) {
+	blockSize := 16
+	if len(request) < 3*blockSize {
+		return nil, fmt.Errorf("Request too short: %d bytes", len(request))
+	}
+	messageLength := len(request) - 2*blockSize
+
+	// the sender is everything between "from=" and the first '&'
+	end := bytes.IndexByte(request[:messageLength], '&')
+	if !bytes.HasPrefix(request, []byte("from=")) || end < 0 || end > blockSize {
+		return nil, fmt.Errorf("Sender is not in the first block")
+	}
+	from := request[len("from="):end]
+	to := []byte(strconv.Itoa(victim))
+	if len(to) != len(from) {
+		return nil, fmt.Errorf("Account %d is not the same length as account %s", victim, from)
+	}
+
+	delta, err := xor.Xor(from, to)
+	if err != nil {
+		return
+	}
+
+	forged = append([]byte{}, request...)
+	copy(forged[len("from="):], to)
+	iv := forged[messageLength : messageLength+blockSize]
+	for i, d := range delta {
+		iv[len("from=")+i] ^= d
+	}
+
+	return
+}
+
+// CbcMacLengthExtension appends a payment of amount to the attacker's
+// account to a captured, signed transaction list, message || mac, using the
+// attacker's own client to sign a second list.
+//
+// With a fixed iv, the MAC of a message is the CBC state after its padded
+// blocks. The captured MAC XORed into the first block of the attacker's
+// message brings the chain back to the same state as signing it from
+// scratch, so pad(captured) || (block0 ^ mac) || rest has the attacker's MAC.
+// The victim's last entry runs straight into the padding and the altered
+// block, with no ';' in between, so the bank can't parse it and drops it:
+// the victim's final transfer is lost.
+// Cryptopals Set 7, Challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+func CbcMacLengthExtension(captured []byte, signer oracle.MacSigner, attacker, amount int) (forged []byte, err error) {
+	blockSize := 16
+	if len(captured) < blockSize {
+		return nil, fmt.Errorf("Captured request too short: %d bytes", len(captured))
+	}
+	message, mac := captured[:len(captured)-blockSize], captured[len(captured)-blockSize:]
+
+	// fill the attacker's first block with a list entry that is harmless
+	// even once scrambled, then start the payment on a fresh entry
+	extension := fmt.Sprintf("from=%d&tx_list=%d:", attacker, attacker)
+	extension += strings.Repeat("0", (blockSize-len(extension)%blockSize)%blockSize)
+	extension += fmt.Sprintf(";%d:%d", attacker, amount)
+
+	tag, err := signer.Sign([]byte(extension))
+	if err != nil {
+		return
+	}
+
+	padded, err := padding.Pkcs7(append([]byte(nil), message...), blockSize)
+	if err != nil {
+		return
+	}
+	glued, err := xor.Xor([]byte(extension[:blockSize]), mac)
+	if err != nil {
+		return
+	}
+
+	forged = append(padded, glued...)
+	forged = append(forged, extension[blockSize:]...)
+
+	return append(forged, tag...), nil
+}
+
+// CbcMacHashForgery returns a message starting with prefix whose
+// cbcmac.Hash is digest.
+//
+// The prefix is padded with spaces to a whole block, then followed by one
+// bridge block. The forgery is block aligned, so it is hashed with a full
+// block of padding; with the key public, ECB decryption walks back from the
+// digest through that padding block to the state the bridge must reach.
+// Bridges containing any of the forbidden bytes (say, a newline ending a
+// JavaScript comment) are avoided by adding another block of spaces.
+// Cryptopals Set 7, Challenge 50
+// https://cryptopals.com/sets/7/challenges/50
+func CbcMacHashForgery(prefix, digest []byte, forbidden string) (forged []byte, err error) {
+	blockSize := cbcmac.BlockSize
+	if len(digest) != blockSize {
+		return nil, fmt.Errorf("Digest has length %d, want %d", len(digest), blockSize)
+	}
+
+	finalPadding := bytes.Repeat([]byte{byte(blockSize)}, blockSize)
+
+	// the CBC state after the bridge block
+	decrypted, err := ecb.Decrypt(digest, cbcmac.HashKey)
+	if err != nil {
+		return
+	}
+	bridged, err := xor.Xor(decrypted, finalPadding)
+	if err != nil {
+		return
+	}
+	bridgeInput, err := ecb.Decrypt(bridged, cbcmac.HashKey)
+	if err != nil {
+		return
+	}
+
+	forged = append([]byte{}, prefix...)
+	forged = append(forged, bytes.Repeat([]byte(" "), (blockSize-len(forged)%blockSize)%blockSize)...)
+	for tries := 0; tries < 256; tries++ {
+		var state []byte
+		state, err = cbc.Encrypt(forged, cbcmac.HashKey, nil)
+		if err != nil {
+			return
+		}
+		if len(state) > 0 {
+			state = state[len(state)-blockSize:]
+		} else {
+			state = make([]byte, blockSize)
+		}
+
+		var bridge []byte
+		bridge, err = xor.Xor(bridgeInput, state)
+		if err != nil {
+			return
+		}
+		if !bytes.ContainsAny(bridge, forbidden) {
+			return append(forged, bridge...), nil
+		}
+
+		forged = append(forged, bytes.Repeat([]byte(" "), blockSize)...)
+	}
+
+	return nil, fmt.Errorf("No bridge block avoids the forbidden bytes %q", forbidden)
+}
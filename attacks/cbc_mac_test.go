@@ -0,0 +1,119 @@
+package attacks
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbcmac"
+)
+
+func TestCbcMacIvForgery(t *testing.T) {
+	tests := []struct {
+		name     string
+		attacker int
+		victim   int
+		wantErr  bool
+	}{
+		{"same_length", 2, 1, false},
+		{"longer_ids", 4242, 1337, false},
+		{"different_length", 2, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank := cbcmac.NewBank()
+			request, _ := bank.Client(tt.attacker).Request(tt.attacker, 1000000)
+
+			forged, err := CbcMacIvForgery(request, tt.victim)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CbcMacIvForgery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := bank.Transfer(forged)
+			if err != nil {
+				t.Errorf("Bank.Transfer() error = %v", err)
+				return
+			}
+			want := []cbcmac.Transfer{{From: tt.victim, To: tt.attacker, Amount: 1000000}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Bank.Transfer(CbcMacIvForgery()) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCbcMacLengthExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		payments []cbcmac.Payment
+		attacker int
+	}{
+		{"one_payment", []cbcmac.Payment{{To: 2, Amount: 100}}, 3},
+		{"block_aligned", []cbcmac.Payment{{To: 2, Amount: 100}, {To: 4, Amount: 123456789}}, 3},
+		{"long_account", []cbcmac.Payment{{To: 2, Amount: 100}, {To: 4, Amount: 5}}, 123456789},
+		{"last_to_attacker", []cbcmac.Payment{{To: 2, Amount: 100}, {To: 4, Amount: 7}, {To: 3, Amount: 555}}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bank := cbcmac.NewBank()
+			captured, _ := bank.Client(1).RequestList(tt.payments)
+
+			forged, err := CbcMacLengthExtension(captured, bank.Client(tt.attacker), tt.attacker, 1000000)
+			if err != nil {
+				t.Errorf("CbcMacLengthExtension() error = %v", err)
+				return
+			}
+
+			got, err := bank.TransferList(forged)
+			if err != nil {
+				t.Errorf("Bank.TransferList() error = %v", err)
+				return
+			}
+			// the victim's last payment is mangled by the glue and dropped
+			var want []cbcmac.Transfer
+			for _, p := range tt.payments[:len(tt.payments)-1] {
+				want = append(want, cbcmac.Transfer{From: 1, To: p.To, Amount: p.Amount})
+			}
+			want = append(want, cbcmac.Transfer{From: 1, To: tt.attacker, Amount: 1000000})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Bank.TransferList(CbcMacLengthExtension()) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestCbcMacHashForgery(t *testing.T) {
+	digest := cbcmac.Hash([]byte("alert('MZA who was that?');\n"))
+
+	tests := []struct {
+		name      string
+		prefix    []byte
+		forbidden string
+	}{
+		{"challenge_50", []byte("alert('Ayo, the Wu is back!');\n//"), "\r\n"},
+		{"block_aligned", []byte("YELLOW SUBMARINE"), ""},
+		{"empty", []byte{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forged, err := CbcMacHashForgery(tt.prefix, digest, tt.forbidden)
+			if err != nil {
+				t.Errorf("CbcMacHashForgery() error = %v", err)
+				return
+			}
+			if !bytes.HasPrefix(forged, tt.prefix) {
+				t.Errorf("CbcMacHashForgery() = %q, want prefix %q", forged, tt.prefix)
+			}
+			if bytes.ContainsAny(forged[len(tt.prefix):], tt.forbidden) {
+				t.Errorf("CbcMacHashForgery() = %q, contains one of %q", forged, tt.forbidden)
+			}
+			if got := cbcmac.Hash(forged); !reflect.DeepEqual(got, digest) {
+				t.Errorf("Hash(CbcMacHashForgery()) = %x, want %x", got, digest)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package attacks
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// fillerAlphabet holds bytes which appear in neither the secret nor the
+// request around it, so filler made from them never compresses against
+// either.
+const fillerAlphabet = "!#$%&()*,;<>?@[]^_`{|}~"
+
+// CompressionOracleAttack recovers the base64 secret following known in the
+// requests of a compression oracle, using only the lengths of its ciphertexts.
+//
+// A guess that extends known correctly compresses slightly better than a
+// wrong one, but usually by less than a byte, and less than a block for
+// CBC. So each guess is sent after random incompressible filler of varying
+// length, until a filler puts the compressed request just on the edge of a
+// byte, or a block, where the right guess alone comes out shorter.
+// The secret ends at the first newline.
+// Cryptopals Set 7, Challenge 51
+// https://cryptopals.com/sets/7/challenges/51
+func CompressionOracleAttack(oracle oracle.EncryptionOracle, known []byte) (secret []byte, err error) {
+	candidates := []byte(base64Alphabet + "\n")
+	guess := append([]byte{}, known...)
+
+	for len(secret) < 1024 {
+		var b byte
+		b, err = nextCompressedByte(oracle, guess, candidates)
+		if err != nil || b == '\n' {
+			return
+		}
+
+		secret = append(secret, b)
+		guess = append(guess, b)
+	}
+
+	return nil, fmt.Errorf("No end to the secret after %d bytes", len(secret))
+}
+
+// nextCompressedByte finds the candidate which best extends known, once it
+// has been the only shortest guess for a few different fillers.
+func nextCompressedByte(oracle oracle.EncryptionOracle, known, candidates []byte) (b byte, err error) {
+	votes := make(map[byte]int)
+	lengths := make([]int, len(candidates))
+
+	for trial := 0; trial < 256; trial++ {
+		filler := randomFiller(trial % 32)
+
+		for i, c := range candidates {
+			content := append(append(append([]byte{}, filler...), known...), c)
+			ciphertext, err := oracle.Encrypt(content)
+			if err != nil {
+				return 0, err
+			}
+			lengths[i] = len(ciphertext)
+		}
+
+		shortest, count := 0, 0
+		for i, l := range lengths {
+			if l < lengths[shortest] {
+				shortest, count = i, 1
+			} else if l == lengths[shortest] {
+				count++
+			}
+		}
+		if count != 1 {
+			continue
+		}
+
+		b = candidates[shortest]
+		votes[b]++
+		if votes[b] == 3 {
+			return b, nil
+		}
+	}
+
+	return 0, fmt.Errorf("No candidate compresses best after %q", known)
+}
+
+func randomFiller(n int) []byte {
+	filler := random.Bytes(n)
+	for i := range filler {
+		filler[i] = fillerAlphabet[int(filler[i])%len(fillerAlphabet)]
+	}
+
+	return filler
+}
@@ -0,0 +1,36 @@
+package attacks
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/compression"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func TestCompressionOracleAttack(t *testing.T) {
+	sessionID := base64.StdEncoding.EncodeToString(random.Bytes(16))
+
+	tests := []struct {
+		name string
+		mode compression.Mode
+	}{
+		{"ctr", compression.Ctr},
+		{"cbc", compression.Cbc},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := compression.NewCompressionOracleWithSession(tt.mode, sessionID)
+
+			got, err := CompressionOracleAttack(oracle, []byte("sessionid="))
+			if err != nil {
+				t.Errorf("CompressionOracleAttack() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(string(got), sessionID) {
+				t.Errorf("CompressionOracleAttack() = %q, want %q", got, sessionID)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package attacks
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// ExpandableMessage is a chain of k collisions, each between a single block
+// and a message of 2^(k-1-i) + 1 blocks. Picking one side of each gives a
+// message of any length from k to k + 2^k - 1 blocks, which always reaches
+// State.
+type ExpandableMessage struct {
+	Short, Long [][]byte
+	State       []byte
+}
+
+// K returns the number of collisions in e.
+func (e ExpandableMessage) K() int {
+	return len(e.Short)
+}
+
+// Message returns the message from e which is the given number of blocks long.
+func (e ExpandableMessage) Message(blocks int) (message []byte, err error) {
+	k := e.K()
+	if blocks < k || blocks > k+(1<<uint(k))-1 {
+		return nil, fmt.Errorf("Expandable message covers %d to %d blocks, not %d", k, k+(1<<uint(k))-1, blocks)
+	}
+
+	extra := blocks - k
+	for i := 0; i < k; i++ {
+		if extra&(1<<uint(k-1-i)) != 0 {
+			message = append(message, e.Long[i]...)
+		} else {
+			message = append(message, e.Short[i]...)
+		}
+	}
+
+	return
+}
+
+// BuildExpandableMessage builds an expandable message of k collisions under
+// h, starting from state, for the cost of k birthday searches.
+// Cryptopals Set 7, Challenge 53
+// https://cryptopals.com/sets/7/challenges/53
+func BuildExpandableMessage(h md.Hash, state []byte, k int) (e ExpandableMessage) {
+	for i := 0; i < k; i++ {
+		// any fixed dummy blocks will do
+		dummy := make([]byte, (1<<uint(k-1-i))*md.BlockSize)
+		dummyState := h.Chain(state, dummy)
+
+		short, long, next := crossCollision(h, state, dummyState)
+		e.Short = append(e.Short, short)
+		e.Long = append(e.Long, append(dummy, long...))
+		state = next
+	}
+	e.State = state
+
+	return
+}
+
+// crossCollision finds a block a from stateA and a block b from stateB
+// which reach the same next state.
+func crossCollision(h md.Hash, stateA, stateB []byte) (a, b, next []byte) {
+	fromA := make(map[string][]byte)
+	fromB := make(map[string][]byte)
+	for {
+		a = random.Bytes(md.BlockSize)
+		outA := string(h.Compress(stateA, a))
+		if b, ok := fromB[outA]; ok {
+			return a, b, []byte(outA)
+		}
+		fromA[outA] = a
+
+		b = random.Bytes(md.BlockSize)
+		outB := string(h.Compress(stateB, b))
+		if a, ok := fromA[outB]; ok {
+			return a, b, []byte(outB)
+		}
+		fromB[outB] = b
+	}
+}
+
+// SecondPreimage finds a different message of the same length as target,
+// with the same hash under h. Long messages make this much cheaper than
+// 2^bits: a bridge block from an expandable message only has to hit any
+// one of target's many intermediate states.
+// It also returns the number of blocks taken from the expandable message.
+// Cryptopals Set 7, Challenge 53
+// https://cryptopals.com/sets/7/challenges/53
+func SecondPreimage(h md.Hash, target []byte) (forged []byte, prefixBlocks int, err error) {
+	n := len(target) / md.BlockSize
+	k := bits.Len(uint(n)) - 1
+	if k < 1 {
+		return nil, 0, fmt.Errorf("Target of %d bytes is too short", len(target))
+	}
+
+	// intermediate states after j blocks, for the j a bridge can land on
+	last := k + (1 << uint(k))
+	if last > n {
+		last = n
+	}
+	states := make(map[string]int)
+	state := h.Iv()
+	for j := 1; j <= last; j++ {
+		state = h.Compress(state, target[(j-1)*md.BlockSize:j*md.BlockSize])
+		if j > k {
+			states[string(state)] = j
+		}
+	}
+
+	e := BuildExpandableMessage(h, h.Iv(), k)
+
+	for {
+		bridge := random.Bytes(md.BlockSize)
+		j, ok := states[string(h.Compress(e.State, bridge))]
+		if !ok {
+			continue
+		}
+
+		prefixBlocks = j - 1
+		forged, err = e.Message(prefixBlocks)
+		if err != nil {
+			return
+		}
+		forged = append(forged, bridge...)
+		forged = append(forged, target[j*md.BlockSize:]...)
+
+		return
+	}
+}
@@ -0,0 +1,67 @@
+package attacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func TestBuildExpandableMessage(t *testing.T) {
+	h, _ := md.New(16)
+	k := 4
+
+	e := BuildExpandableMessage(h, h.Iv(), k)
+	for blocks := k; blocks < k+(1<<uint(k)); blocks++ {
+		message, err := e.Message(blocks)
+		if err != nil {
+			t.Errorf("ExpandableMessage.Message(%d) error = %v", blocks, err)
+			continue
+		}
+		if len(message) != blocks*md.BlockSize {
+			t.Errorf("ExpandableMessage.Message(%d) has %d bytes, want %d", blocks, len(message), blocks*md.BlockSize)
+		}
+		if got := h.Chain(h.Iv(), message); !reflect.DeepEqual(got, e.State) {
+			t.Errorf("Chain(ExpandableMessage.Message(%d)) = %x, want %x", blocks, got, e.State)
+		}
+	}
+
+	for _, blocks := range []int{k - 1, k + (1 << uint(k))} {
+		if _, err := e.Message(blocks); err == nil {
+			t.Errorf("ExpandableMessage.Message(%d) error = nil, want out of range", blocks)
+		}
+	}
+}
+
+func TestSecondPreimage(t *testing.T) {
+	tests := []struct {
+		name   string
+		bits   int
+		length int
+	}{
+		{"16_bits", 16, 1 << 8 * md.BlockSize},
+		{"24_bits_partial_block", 24, 1<<10*md.BlockSize + 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := md.New(tt.bits)
+			target := random.Bytes(tt.length)
+
+			forged, prefixBlocks, err := SecondPreimage(h, target)
+			if err != nil {
+				t.Errorf("SecondPreimage() error = %v", err)
+				return
+			}
+			if len(forged) != len(target) || reflect.DeepEqual(forged, target) {
+				t.Errorf("SecondPreimage() = %d bytes, want a different message of %d bytes", len(forged), len(target))
+			}
+			if prefixBlocks < 1 || prefixBlocks >= len(target)/md.BlockSize {
+				t.Errorf("SecondPreimage() prefix of %d blocks, out of range", prefixBlocks)
+			}
+			if got, want := h.Sum(forged), h.Sum(target); !reflect.DeepEqual(got, want) {
+				t.Errorf("Hash.Sum(SecondPreimage()) = %x, want %x", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package attacks
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+// ErrNoTimingSignal is returned by TimingAttack when it runs out of retries
+// without any candidate byte standing out.
+var ErrNoTimingSignal = errors.New("No timing signal")
+
+// TimingAttackConfig tunes TimingAttack. Zero fields take the defaults.
+type TimingAttackConfig struct {
+	// Samples is the number of timings taken for each candidate byte
+	// before looking for a winner. Default 3.
+	Samples int
+	// MaxSamples is the number of timings per candidate after which a byte
+	// with no clear winner is retried more carefully. Each retry doubles it,
+	// until the next byte is found. Default 48.
+	MaxSamples int
+	// Contenders is the number of leading candidates that keep being timed
+	// after the first round, when no candidate stands out. Default 16.
+	Contenders int
+	// Workers is the number of queries in flight at once. Sleeps on the
+	// server don't compete for CPU, so this can be above NumCPU, but queued
+	// requests add noise. Each retry halves it for the rest of the attack.
+	// Default 16 * NumCPU.
+	Workers int
+	// MaxRetries is the number of retries, over the whole attack, after
+	// which TimingAttack gives up with ErrNoTimingSignal. Default 32.
+	MaxRetries int
+}
+
+func (config TimingAttackConfig) withDefaults() TimingAttackConfig {
+	if config.Samples <= 0 {
+		config.Samples = 3
+	}
+	if config.MaxSamples <= 0 {
+		config.MaxSamples = 48
+	}
+	if config.MaxSamples < config.Samples {
+		config.MaxSamples = config.Samples
+	}
+	if config.Contenders <= 0 || config.Contenders > 256 {
+		config.Contenders = 16
+	}
+	if config.Workers <= 0 {
+		config.Workers = 16 * runtime.NumCPU()
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 32
+	}
+
+	return config
+}
+
+// retry returns config with twice the samples and half the workers, for
+// measuring again after a byte showed no clear winner.
+func (config TimingAttackConfig) retry() TimingAttackConfig {
+	config.MaxSamples *= 2
+	if config.Workers > 1 {
+		config.Workers /= 2
+	}
+
+	return config
+}
+
+// TimingAttack recovers a valid MAC of macSize bytes for message from a
+// verifier that compares MACs a byte at a time, using how long it takes to
+// reject a guess to learn how many leading bytes were right.
+//
+// Each candidate for the next byte is timed several times and ranked by its
+// median, which ignores the occasional slow request. If no candidate stands
+// clear of the rest, the leading contenders are timed again with more
+// samples. If there is still no signal, either the noise is too high or
+// the previous byte is wrong, so the previous byte is measured again with
+// more samples and fewer queries in flight, up to config.MaxRetries times.
+// Cryptopals Set 4, Challenges 31 and 32
+// https://cryptopals.com/sets/4/challenges/31
+// https://cryptopals.com/sets/4/challenges/32
+func TimingAttack(verifier oracle.MacVerifier, message []byte, macSize int, config TimingAttackConfig) (mac []byte, err error) {
+	config = config.withDefaults()
+	current := config
+	mac = make([]byte, macSize)
+
+	retries := 0
+	for i := 0; i < macSize; {
+		var ok bool
+		if i == macSize-1 {
+			// the last byte doesn't change the timing, but it does change the answer
+			ok, err = findLastByte(verifier, message, mac)
+			if ok || err != nil {
+				return
+			}
+		} else {
+			var b byte
+			b, ok, err = timeNextByte(verifier, message, mac[:i], macSize, current)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				mac[i] = b
+				i++
+				current.MaxSamples = config.MaxSamples
+				continue
+			}
+		}
+
+		// no signal: measure the previous byte again, more carefully
+		if retries >= config.MaxRetries {
+			return nil, ErrNoTimingSignal
+		}
+		retries++
+		current = current.retry()
+		if i > 0 {
+			i--
+		}
+	}
+
+	return
+}
+
+// findLastByte tries every value for the last byte of mac.
+func findLastByte(verifier oracle.MacVerifier, message, mac []byte) (ok bool, err error) {
+	for b := 0; b < 256; b++ {
+		mac[len(mac)-1] = byte(b)
+		ok, err = verifier.Verify(message, mac)
+		if ok || err != nil {
+			return
+		}
+	}
+
+	return false, nil
+}
+
+// timing is one timed query for candidate byte b.
+type timing struct {
+	b       byte
+	elapsed time.Duration
+	err     error
+}
+
+// timeNextByte times every candidate for the byte after known, then keeps
+// doubling the samples of the leading contenders until one stands out or
+// config.MaxSamples is reached.
+func timeNextByte(verifier oracle.MacVerifier, message, known []byte, macSize int, config TimingAttackConfig) (b byte, ok bool, err error) {
+	samples := make([][]time.Duration, 256)
+	candidates := make([]byte, 256)
+	for i := range candidates {
+		candidates[i] = byte(i)
+	}
+
+	taken := 0
+	for n := config.Samples; taken < config.MaxSamples; n *= 2 {
+		if n > config.MaxSamples {
+			n = config.MaxSamples
+		}
+
+		err = sampleCandidates(verifier, message, known, macSize, candidates, n-taken, config.Workers, samples)
+		if err != nil {
+			return
+		}
+		taken = n
+
+		b, ok = standout(samples)
+		if ok {
+			return
+		}
+		candidates = rank(samples)[:config.Contenders]
+	}
+
+	return
+}
+
+// sampleCandidates times each of candidates count more times, appending the
+// results to samples.
+func sampleCandidates(verifier oracle.MacVerifier, message, known []byte, macSize int, candidates []byte, count, workers int, samples [][]time.Duration) error {
+	jobs := make(chan byte, workers)
+	results := make(chan timing, workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			guess := make([]byte, macSize)
+			copy(guess, known)
+			for b := range jobs {
+				guess[len(known)] = b
+				start := time.Now()
+				_, err := verifier.Verify(message, guess)
+				results <- timing{b, time.Since(start), err}
+			}
+		}()
+	}
+
+	// interleave the candidates so that drift in the server's speed
+	// affects them all alike
+	go func() {
+		for i := 0; i < count; i++ {
+			for _, b := range candidates {
+				jobs <- b
+			}
+		}
+		close(jobs)
+	}()
+
+	var err error
+	for i := 0; i < len(candidates)*count; i++ {
+		t := <-results
+		if t.err != nil && err == nil {
+			err = t.err
+		}
+		samples[t.b] = append(samples[t.b], t.elapsed)
+	}
+
+	return err
+}
+
+// standout returns the candidate with the slowest median time, if it is
+// clearly separated from the runner-up: by several times the typical spread
+// of the candidates' medians. Contenders timed more often than the rest have
+// steadier medians, so the spread is scaled down by the square root of the
+// extra samples.
+func standout(samples [][]time.Duration) (b byte, ok bool) {
+	medians := mediansOf(samples)
+	order := rank(samples)
+
+	best, second := medians[order[0]], medians[order[1]]
+	typical := median(medians)
+
+	deviations := make([]time.Duration, len(medians))
+	for i, m := range medians {
+		deviations[i] = m - typical
+		if deviations[i] < 0 {
+			deviations[i] = -deviations[i]
+		}
+	}
+	spread := float64(median(deviations))
+
+	fewest, leading := len(samples[0]), len(samples[order[1]])
+	for _, s := range samples {
+		if len(s) < fewest {
+			fewest = len(s)
+		}
+	}
+	if len(samples[order[0]]) < leading {
+		leading = len(samples[order[0]])
+	}
+	if leading > 0 {
+		spread *= math.Sqrt(float64(fewest) / float64(leading))
+	}
+
+	gap := best - second
+	ok = float64(gap) > 4*spread
+
+	return order[0], ok
+}
+
+// rank orders the candidates by median time, slowest first.
+func rank(samples [][]time.Duration) (order []byte) {
+	medians := mediansOf(samples)
+
+	order = make([]byte, len(medians))
+	for i := range order {
+		order[i] = byte(i)
+	}
+	sort.Slice(order, func(i, j int) bool { return medians[order[i]] > medians[order[j]] })
+
+	return
+}
+
+func mediansOf(samples [][]time.Duration) []time.Duration {
+	medians := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		medians[i] = median(s)
+	}
+
+	return medians
+}
+
+func median(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted[len(sorted)/2]
+}
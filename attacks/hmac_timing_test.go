@@ -0,0 +1,74 @@
+package attacks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/hash/hmac"
+)
+
+func TestTimingAttack(t *testing.T) {
+	if testing.Short() {
+		t.Skip("timing attack takes tens of seconds")
+	}
+
+	s := hmac.NewServer(5 * time.Millisecond)
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	config := TimingAttackConfig{}.withDefaults()
+	// keep connections open, so the attack doesn't run out of ports
+	client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: config.Workers}}
+	verifier := hmac.Client{URL: server.URL + "/test", HTTPClient: client}
+
+	file := []byte("foo")
+	mac, err := TimingAttack(verifier, file, 20, config)
+	if err != nil {
+		t.Errorf("TimingAttack() error = %v", err)
+		return
+	}
+	if valid, _ := s.Verify(file, mac); !valid {
+		t.Errorf("TimingAttack() = %x, not a valid MAC", mac)
+	}
+}
+
+func TestTimingAttack_noSignal(t *testing.T) {
+	// a constant-time server never shows a winner
+	s := hmac.NewServer(0)
+
+	_, err := TimingAttack(s, []byte("foo"), 20, TimingAttackConfig{MaxRetries: 3})
+	if err != ErrNoTimingSignal {
+		t.Errorf("TimingAttack() error = %v, want %v", err, ErrNoTimingSignal)
+	}
+}
+
+func Test_standout(t *testing.T) {
+	flat := make([][]time.Duration, 256)
+	for i := range flat {
+		flat[i] = []time.Duration{time.Millisecond + time.Duration(i%7)*time.Microsecond}
+	}
+
+	clear := make([][]time.Duration, 256)
+	copy(clear, flat)
+	clear[0x42] = []time.Duration{3 * time.Millisecond}
+
+	tests := []struct {
+		name    string
+		samples [][]time.Duration
+		wantB   byte
+		wantOk  bool
+	}{
+		{"no_signal", flat, 0, false},
+		{"clear_winner", clear, 0x42, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotB, gotOk := standout(tt.samples)
+			if gotOk != tt.wantOk || (tt.wantOk && gotB != tt.wantB) {
+				t.Errorf("standout() = %x, %v, want %x, %v", gotB, gotOk, tt.wantB, tt.wantOk)
+			}
+		})
+	}
+}
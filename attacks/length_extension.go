@@ -0,0 +1,91 @@
+package attacks
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+	"github.com/adavidalbertson/cryptopals/hash/sha1"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+// ExtendableHash describes a Merkle-Damgard hash well enough to extend its
+// digests: how it pads a message, and how to resume hashing from a digest.
+// The hash families differ only in these two steps (SHA-1 writes its length
+// and registers big-endian, MD4 little-endian), so one attack covers both.
+type ExtendableHash struct {
+	Name string
+	// Pad returns the padding appended to a message of length bytes.
+	Pad func(length uint64) []byte
+	// Resume returns a hash in the state left by a message of length bytes
+	// (a multiple of the block size) whose digest is sum.
+	Resume func(sum []byte, length uint64) (hash.Hash, error)
+}
+
+// Sha1 is SHA-1, for length extension attacks.
+var Sha1 = ExtendableHash{
+	"SHA-1",
+	sha1.Pad,
+	func(sum []byte, length uint64) (hash.Hash, error) {
+		state, err := sha1.StateFromSum(sum, length)
+		return sha1.NewFromState(state), err
+	},
+}
+
+// Md4 is MD4, for length extension attacks.
+var Md4 = ExtendableHash{
+	"MD4",
+	md4.Pad,
+	func(sum []byte, length uint64) (hash.Hash, error) {
+		state, err := md4.StateFromSum(sum, length)
+		return md4.NewFromState(state), err
+	},
+}
+
+// LengthExtend returns the digest of prefix || glue || suffix, where sum is
+// the digest of a prefix of prefixLength bytes and glue is its padding,
+// without knowing the prefix itself.
+func LengthExtend(h ExtendableHash, sum []byte, prefixLength uint64, suffix []byte) (glue, extended []byte, err error) {
+	glue = h.Pad(prefixLength)
+
+	resumed, err := h.Resume(sum, prefixLength+uint64(len(glue)))
+	if err != nil {
+		return
+	}
+
+	resumed.Write(suffix)
+
+	return glue, resumed.Sum(nil), nil
+}
+
+// LengthExtensionAttack forges a secret-prefix MAC, H(key || message), for
+// message || glue padding || suffix, given a valid tag for message.
+// The key length is unknown, so each length up to maxKeyLength is tried
+// until the verifier accepts the forgery.
+// Cryptopals Set 4, Challenges 29 and 30
+// https://cryptopals.com/sets/4/challenges/29
+// https://cryptopals.com/sets/4/challenges/30
+func LengthExtensionAttack(h ExtendableHash, verifier oracle.MacVerifier, message, tag, suffix []byte, maxKeyLength int) (forgedMessage, forgedTag []byte, err error) {
+	for keyLength := 0; keyLength <= maxKeyLength; keyLength++ {
+		var glue []byte
+		glue, forgedTag, err = LengthExtend(h, tag, uint64(keyLength+len(message)), suffix)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		forgedMessage = append([]byte{}, message...)
+		forgedMessage = append(forgedMessage, glue...)
+		forgedMessage = append(forgedMessage, suffix...)
+
+		var valid bool
+		valid, err = verifier.Verify(forgedMessage, forgedTag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if valid {
+			return forgedMessage, forgedTag, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("No key length up to %d gave a valid %s forgery", maxKeyLength, h.Name)
+}
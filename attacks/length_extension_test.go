@@ -0,0 +1,90 @@
+package attacks
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+	"github.com/adavidalbertson/cryptopals/hash/sha1"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+type macOracle interface {
+	oracle.MacSigner
+	oracle.MacVerifier
+}
+
+func TestLengthExtend(t *testing.T) {
+	prefix := []byte("YELLOW SUBMARINE")
+	suffix := []byte(";admin=true")
+
+	sha1Sum := sha1.Sum(prefix)
+	md4Sum := md4.Sum(prefix)
+	sha1Glued := append(append(append([]byte{}, prefix...), sha1.Pad(uint64(len(prefix)))...), suffix...)
+	md4Glued := append(append(append([]byte{}, prefix...), md4.Pad(uint64(len(prefix)))...), suffix...)
+	sha1Want := sha1.Sum(sha1Glued)
+	md4Want := md4.Sum(md4Glued)
+
+	tests := []struct {
+		name string
+		h    ExtendableHash
+		sum  []byte
+		want []byte
+	}{
+		{"sha1", Sha1, sha1Sum[:], sha1Want[:]},
+		{"md4", Md4, md4Sum[:], md4Want[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := LengthExtend(tt.h, tt.sum, uint64(len(prefix)), suffix)
+			if err != nil {
+				t.Errorf("LengthExtend() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LengthExtend() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLengthExtensionAttack(t *testing.T) {
+	message := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	suffix := []byte(";admin=true")
+
+	// NewMacOracle keys are at least 8 bytes
+	tests := []struct {
+		name         string
+		h            ExtendableHash
+		mac          macOracle
+		maxKeyLength int
+		wantErr      bool
+	}{
+		{"challenge_29", Sha1, sha1.NewMacOracle(), 64, false},
+		{"challenge_30", Md4, md4.NewMacOracle(), 64, false},
+		{"wrong_hash", Md4, sha1.NewMacOracle(), 64, true},
+		{"key_longer_than_search", Sha1, sha1.NewMacOracle(), 4, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, _ := tt.mac.Sign(message)
+
+			forgedMessage, forgedTag, err := LengthExtensionAttack(tt.h, tt.mac, message, tag, suffix, tt.maxKeyLength)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LengthExtensionAttack() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			if !bytes.HasPrefix(forgedMessage, message) || !bytes.HasSuffix(forgedMessage, suffix) {
+				t.Errorf("LengthExtensionAttack() message = %q, want %q...%q", forgedMessage, message, suffix)
+			}
+			if valid, _ := tt.mac.Verify(forgedMessage, forgedTag); !valid {
+				t.Errorf("Verify(LengthExtensionAttack()) = false, want true")
+			}
+		})
+	}
+}
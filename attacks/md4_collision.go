@@ -0,0 +1,251 @@
+package attacks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// md4Condition constrains one bit of an MD4 step output, counted from 1 as
+// in Wang et al.: to value if ref is 0, or otherwise to the same bit of the
+// output ref steps earlier, XOR value.
+type md4Condition struct {
+	bit   uint
+	ref   int
+	value uint32
+}
+
+func md4Zero(bit uint) md4Condition       { return md4Condition{bit, 0, 0} }
+func md4One(bit uint) md4Condition        { return md4Condition{bit, 0, 1} }
+func md4Prev(bit uint) md4Condition       { return md4Condition{bit, 1, 0} }
+func md4SecondPrev(bit uint) md4Condition { return md4Condition{bit, 2, 0} }
+
+// md4Conditions holds the sufficient conditions of Wang et al.'s
+// differential path, indexed like the step outputs q: q[0:4] is the
+// initial a0, d0, c0, b0, q[4:20] round 1 (a1 to b4), and q[20:] round 2.
+var md4Conditions = map[int][]md4Condition{
+	// a1
+	4: {md4Prev(7)},
+	// d1
+	5: {md4Zero(7), md4Prev(8), md4Prev(11)},
+	// c1
+	6: {md4One(7), md4One(8), md4Zero(11), md4Prev(26)},
+	// b1
+	7: {md4One(7), md4Zero(8), md4Zero(11), md4Zero(26)},
+	// a2
+	8: {md4One(8), md4One(11), md4Zero(26), md4Prev(14)},
+	// d2
+	9: {md4Zero(14), md4Prev(19), md4Prev(20), md4Prev(21), md4Prev(22), md4One(26)},
+	// c2
+	10: {md4Prev(13), md4Zero(14), md4Prev(15), md4Zero(19), md4Zero(20), md4One(21), md4Zero(22)},
+	// b2
+	11: {md4One(13), md4One(14), md4Zero(15), md4Prev(17), md4Zero(19), md4Zero(20), md4Zero(21), md4Zero(22)},
+	// a3
+	12: {md4One(13), md4One(14), md4One(15), md4Zero(17), md4Zero(19), md4Zero(20), md4Zero(21), md4One(22), md4Prev(23), md4Prev(26)},
+	// d3
+	13: {md4One(13), md4One(14), md4One(15), md4Zero(17), md4Zero(20), md4One(21), md4One(22), md4Zero(23), md4One(26), md4Prev(30)},
+	// c3
+	14: {md4One(17), md4Zero(20), md4Zero(21), md4Zero(22), md4Zero(23), md4Zero(26), md4One(30), md4Prev(32)},
+	// b3
+	15: {md4Zero(20), md4One(21), md4One(22), md4Prev(23), md4One(26), md4Zero(30), md4Zero(32)},
+	// a4
+	16: {md4Zero(23), md4Zero(26), md4Prev(27), md4Prev(29), md4One(30), md4Zero(32)},
+	// d4
+	17: {md4Zero(23), md4Zero(26), md4One(27), md4One(29), md4Zero(30), md4One(32)},
+	// c4
+	18: {md4Prev(19), md4One(23), md4One(26), md4Zero(27), md4Zero(29), md4Zero(30)},
+	// b4
+	19: {md4Zero(19), md4One(26), md4One(27), md4One(29), md4Zero(30)},
+	// a5
+	20: {md4SecondPrev(19), md4Prev(26), md4SecondPrev(27), md4Prev(29), md4Prev(32)},
+	// d5
+	21: {md4Prev(19), md4SecondPrev(26), md4SecondPrev(27), md4SecondPrev(29), md4SecondPrev(32)},
+}
+
+// want returns the mask of the bit c constrains in q[i], and the value
+// that bit should have.
+func (c md4Condition) want(q []uint32, i int) (mask, want uint32) {
+	mask = uint32(1) << (c.bit - 1)
+	want = c.value << (c.bit - 1)
+	if c.ref != 0 {
+		want ^= q[i-c.ref] & mask
+	}
+
+	return
+}
+
+// md4Fix sets the bits of q[i] its conditions call for.
+func md4Fix(q []uint32, i int) {
+	for _, c := range md4Conditions[i] {
+		mask, want := c.want(q, i)
+		q[i] = q[i]&^mask | want
+	}
+}
+
+// md4Satisfied reports whether q[i] meets its conditions.
+func md4Satisfied(q []uint32, i int) bool {
+	for _, c := range md4Conditions[i] {
+		if mask, want := c.want(q, i); q[i]&mask != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+// md4SatisfiedAll reports whether every step output in q meets its
+// conditions.
+func md4SatisfiedAll(q []uint32) bool {
+	for i := range q {
+		if !md4Satisfied(q, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+const (
+	md4Round2Constant = 0x5A827999
+)
+
+var (
+	md4Round1Shifts = [4]int{3, 7, 11, 19}
+	md4Round2Shifts = [4]int{3, 5, 9, 13}
+)
+
+func md4F(x, y, z uint32) uint32 {
+	return (x & y) | (^x & z)
+}
+
+func md4G(x, y, z uint32) uint32 {
+	return (x & y) | (x & z) | (y & z)
+}
+
+// md4Step1 returns the output of round 1 step i, q[i+4].
+func md4Step1(q []uint32, m *[16]uint32, i int) uint32 {
+	return bits.RotateLeft32(q[i]+md4F(q[i+3], q[i+2], q[i+1])+m[i], md4Round1Shifts[i%4])
+}
+
+// md4Word1 solves for the message word of round 1 step i which gives q[i+4].
+func md4Word1(q []uint32, i int) uint32 {
+	return bits.RotateLeft32(q[i+4], -md4Round1Shifts[i%4]) - q[i] - md4F(q[i+3], q[i+2], q[i+1])
+}
+
+// md4Step2 returns the output of round 2 step i, q[i+20], from message word
+// w, which is m[0], m[4], m[8]... in turn.
+func md4Step2(q []uint32, w uint32, i int) uint32 {
+	return bits.RotateLeft32(q[i+16]+md4G(q[i+19], q[i+18], q[i+17])+w+md4Round2Constant, md4Round2Shifts[i%4])
+}
+
+// md4Word2 solves for the message word of round 2 step i which gives q[i+20].
+func md4Word2(q []uint32, i int) uint32 {
+	return bits.RotateLeft32(q[i+20], -md4Round2Shifts[i%4]) - q[i+16] - md4G(q[i+19], q[i+18], q[i+17]) - md4Round2Constant
+}
+
+// md4Partner returns the message which Wang et al.'s differential path
+// pairs with m: m[1] + 2^31, m[2] + 2^31 - 2^28, m[12] - 2^16.
+func md4Partner(m [16]uint32) [16]uint32 {
+	m[1] += 1 << 31
+	m[2] += 1<<31 - 1<<28
+	m[12] -= 1 << 16
+
+	return m
+}
+
+func md4Block(m [16]uint32) []byte {
+	block := make([]byte, md4.BlockSize)
+	for i, w := range m {
+		binary.LittleEndian.PutUint32(block[4*i:], w)
+	}
+
+	return block
+}
+
+// Md4CollisionStats counts the candidate messages an MD4 collision search
+// tried before one collided.
+type Md4CollisionStats struct {
+	Attempts uint64
+	Elapsed  time.Duration
+}
+
+func (s Md4CollisionStats) String() string {
+	return fmt.Sprintf("%d attempts in %v", s.Attempts, s.Elapsed)
+}
+
+// Md4Collision finds two different one-block messages with the same MD4
+// digest, following Wang et al.'s differential path. Each candidate is a
+// random block, modified to meet the path's conditions in round 1, then
+// those on a5 and d5 in round 2. Those last fix-ups change a1 and a2, so a
+// candidate whose round 1 conditions no longer hold is dropped; about one
+// attempt in 2^19 ends in a collision. It gives up after maxAttempts
+// candidates.
+// Cryptopals Set 7, Challenge 55
+// https://cryptopals.com/sets/7/challenges/55
+func Md4Collision(maxAttempts uint64) (a, b []byte, stats Md4CollisionStats, err error) {
+	start := time.Now()
+	defer func() {
+		stats.Elapsed = time.Since(start)
+	}()
+
+	rng := rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(random.Bytes(8)))))
+	iv := md4.New().State().H
+
+	q := make([]uint32, 22)
+	q[0], q[1], q[2], q[3] = iv[0], iv[3], iv[2], iv[1]
+
+	for stats.Attempts < maxAttempts {
+		stats.Attempts++
+
+		var m [16]uint32
+		for i := range m {
+			m[i] = rng.Uint32()
+		}
+
+		// single-step modification: fix each round 1 output in turn, then
+		// solve for the word which produces it
+		for i := 0; i < 16; i++ {
+			q[i+4] = md4Step1(q, &m, i)
+			md4Fix(q, i+4)
+			m[i] = md4Word1(q, i)
+		}
+
+		// multi-step modification of a5 through m[0], then of d5 through
+		// m[4]; the following round 1 words are solved again so only a1, or
+		// a2, changes
+		q[20] = md4Step2(q, m[0], 0)
+		md4Fix(q, 20)
+		m[0] = md4Word2(q, 0)
+		q[4] = md4Step1(q, &m, 0)
+		for i := 1; i < 5; i++ {
+			m[i] = md4Word1(q, i)
+		}
+
+		q[21] = md4Step2(q, m[4], 1)
+		md4Fix(q, 21)
+		m[4] = md4Word2(q, 1)
+		q[8] = md4Step1(q, &m, 4)
+		for i := 5; i < 9; i++ {
+			m[i] = md4Word1(q, i)
+		}
+
+		// the new a1 and a2 can carry into bits with round 1 conditions
+		if !md4SatisfiedAll(q) {
+			continue
+		}
+
+		a, b = md4Block(m), md4Block(md4Partner(m))
+		sumA, sumB := md4.Sum(a), md4.Sum(b)
+		if bytes.Equal(sumA[:], sumB[:]) {
+			return
+		}
+	}
+
+	return nil, nil, stats, fmt.Errorf("No MD4 collision in %d attempts", maxAttempts)
+}
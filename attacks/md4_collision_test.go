@@ -0,0 +1,34 @@
+package attacks
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+)
+
+func TestMd4Collision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes a few seconds")
+	}
+
+	a, b, stats, err := Md4Collision(1 << 30)
+	if err != nil {
+		t.Fatalf("Md4Collision() error = %v", err)
+	}
+	t.Log(stats)
+
+	if bytes.Equal(a, b) {
+		t.Errorf("Md4Collision() = %x, %x, want different messages", a, b)
+	}
+	if sumA, sumB := md4.Sum(a), md4.Sum(b); sumA != sumB {
+		t.Errorf("md4.Sum() = %x, %x, want equal", sumA, sumB)
+	}
+}
+
+func TestMd4Collision_giveUp(t *testing.T) {
+	_, _, stats, err := Md4Collision(0)
+	if err == nil || stats.Attempts != 0 {
+		t.Errorf("Md4Collision(0) = %v, error %v, want 0 attempts and an error", stats, err)
+	}
+}
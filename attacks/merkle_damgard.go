@@ -0,0 +1,95 @@
+package attacks
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// BlockCollision finds two different blocks which take state to the same
+// next state under h, by the birthday bound: about 2^(bits/2) tries.
+// Cryptopals Set 7, Challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+func BlockCollision(h md.Hash, state []byte) (a, b, next []byte) {
+	seen := make(map[string][]byte)
+	for {
+		block := random.Bytes(md.BlockSize)
+		out := h.Compress(state, block)
+
+		if other, ok := seen[string(out)]; ok && string(other) != string(block) {
+			return other, block, out
+		}
+		seen[string(out)] = block
+	}
+}
+
+// Multicollision is a chain of colliding block pairs. Picking either block
+// of each pair gives 2^len(Pairs) messages, which all reach State.
+type Multicollision struct {
+	Pairs [][2][]byte
+	State []byte
+}
+
+// Count returns the number of messages in m.
+func (m Multicollision) Count() uint64 {
+	return 1 << uint(len(m.Pairs))
+}
+
+// Message returns the i-th message of m: bit j of i picks the block for
+// pair j.
+func (m Multicollision) Message(i uint64) []byte {
+	message := make([]byte, 0, len(m.Pairs)*md.BlockSize)
+	for j, pair := range m.Pairs {
+		message = append(message, pair[(i>>uint(j))&1]...)
+	}
+
+	return message
+}
+
+// JouxMulticollision builds 2^n messages of n blocks which all collide
+// under h from state, for the cost of only n block collisions.
+// Cryptopals Set 7, Challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+func JouxMulticollision(h md.Hash, state []byte, n int) (m Multicollision) {
+	m.State = state
+	for i := 0; i < n; i++ {
+		m = m.extend(h)
+	}
+
+	return
+}
+
+// extend adds one more colliding pair to m, doubling its messages.
+func (m Multicollision) extend(h md.Hash) Multicollision {
+	a, b, next := BlockCollision(h, m.State)
+
+	return Multicollision{append(m.Pairs[:len(m.Pairs):len(m.Pairs)], [2][]byte{a, b}), next}
+}
+
+// CascadeCollision finds two messages with the same digest under c.
+//
+// A Joux multicollision in the cheap hash F with 2^(bits of G/2) messages is
+// likely to contain a collision in G. If it doesn't, one more stage
+// doubles the messages to search.
+// Cryptopals Set 7, Challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+func CascadeCollision(c md.Cascade) (a, b []byte, err error) {
+	stages := c.G.Size() * 8 / 2
+	m := JouxMulticollision(c.F, c.F.Iv(), stages)
+
+	for tries := 0; tries < 4; tries++ {
+		seen := make(map[string]uint64)
+		for i := uint64(0); i < m.Count(); i++ {
+			digest := string(c.G.Sum(m.Message(i)))
+			if j, ok := seen[digest]; ok {
+				return m.Message(j), m.Message(i), nil
+			}
+			seen[digest] = i
+		}
+
+		m = m.extend(c.F)
+	}
+
+	return nil, nil, fmt.Errorf("No collision in G among %d collisions in F", m.Count()/2)
+}
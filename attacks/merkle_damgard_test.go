@@ -0,0 +1,63 @@
+package attacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+)
+
+func TestBlockCollision(t *testing.T) {
+	h, _ := md.New(24)
+	state := h.Iv()
+
+	a, b, next := BlockCollision(h, state)
+	if reflect.DeepEqual(a, b) {
+		t.Errorf("BlockCollision() = %x, %x, want different blocks", a, b)
+	}
+	if gotA, gotB := h.Compress(state, a), h.Compress(state, b); !reflect.DeepEqual(gotA, next) || !reflect.DeepEqual(gotB, next) {
+		t.Errorf("BlockCollision() next = %x, want %x and %x", next, gotA, gotB)
+	}
+}
+
+func TestJouxMulticollision(t *testing.T) {
+	h, _ := md.New(16)
+	n := 5
+
+	m := JouxMulticollision(h, h.Iv(), n)
+	if m.Count() != 1<<uint(n) {
+		t.Errorf("JouxMulticollision().Count() = %d, want %d", m.Count(), 1<<uint(n))
+	}
+
+	want := h.Sum(m.Message(0))
+	seen := make(map[string]bool)
+	for i := uint64(0); i < m.Count(); i++ {
+		message := m.Message(i)
+		if seen[string(message)] {
+			t.Errorf("JouxMulticollision().Message(%d) repeats an earlier message", i)
+		}
+		seen[string(message)] = true
+
+		if got := h.Sum(message); !reflect.DeepEqual(got, want) {
+			t.Errorf("Hash.Sum(JouxMulticollision().Message(%d)) = %x, want %x", i, got, want)
+		}
+	}
+}
+
+func TestCascadeCollision(t *testing.T) {
+	f, _ := md.New(16)
+	g, _ := md.New(32)
+	c := md.Cascade{F: f, G: g}
+
+	a, b, err := CascadeCollision(c)
+	if err != nil {
+		t.Errorf("CascadeCollision() error = %v", err)
+		return
+	}
+	if reflect.DeepEqual(a, b) {
+		t.Errorf("CascadeCollision() = %x, %x, want different messages", a, b)
+	}
+	if gotA, gotB := c.Sum(a), c.Sum(b); !reflect.DeepEqual(gotA, gotB) {
+		t.Errorf("Cascade.Sum() = %x and %x, want a collision", gotA, gotB)
+	}
+}
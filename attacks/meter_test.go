@@ -0,0 +1,66 @@
+package attacks
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random/MT19937"
+)
+
+// Attacks must hand back the error from an oracle that stops answering,
+// not whatever goes wrong later with the answers they didn't get.
+func TestAttacks_budgetExceeded(t *testing.T) {
+	suffix, _ := base64.StdEncoding.DecodeString("Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwo=")
+	ecbOracle, _ := ecb.NewAesEcbOracle(suffix, true)
+
+	paddingOracle := cbc.NewPaddingOracle()
+	ciphertext, iv, _ := paddingOracle.Encrypt()
+
+	mtOracle := MT19937.NewCtrOracle()
+
+	tests := []struct {
+		name   string
+		budget int
+		attack func(m *oracle.Meter) error
+	}{
+		{"ecb_detect_block_size", 1, func(m *oracle.Meter) error {
+			_, err := AesEcbOracleDetectBlockSize(m.EncryptionOracle(ecbOracle))
+			return err
+		}},
+		{"ecb_early", 50, func(m *oracle.Meter) error {
+			_, err := AesEcbOracleBreak(m.EncryptionOracle(ecbOracle))
+			return err
+		}},
+		{"ecb_late", 2000, func(m *oracle.Meter) error {
+			_, err := AesEcbOracleBreak(m.EncryptionOracle(ecbOracle))
+			return err
+		}},
+		{"padding", 100, func(m *oracle.Meter) error {
+			_, err := PaddingOracleAttack(m.PaddingValidator(paddingOracle), ciphertext, iv)
+			return err
+		}},
+		{"profile", 10, func(m *oracle.Meter) error {
+			_, err := ProfileSpoofAdmin(m.ProfileOracle(ecb.NewProfileMaker()))
+			return err
+		}},
+		{"mt19937", 1, func(m *oracle.Meter) error {
+			// spend the whole budget before the attack starts
+			o := m.EncryptionOracle(&mtOracle)
+			o.Encrypt(nil)
+			_, err := BreakMT19937CtrOracle(o)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meter := &oracle.Meter{Budget: tt.budget}
+			if err := tt.attack(meter); !errors.Is(err, oracle.ErrBudgetExceeded) {
+				t.Errorf("error = %v, want %v", err, oracle.ErrBudgetExceeded)
+			}
+		})
+	}
+}
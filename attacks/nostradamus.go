@@ -0,0 +1,164 @@
+package attacks
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// Diamond is a binary tree of collisions: 2^k leaf states, each of which
+// reaches the root state through k blocks.
+// States[0] holds the leaves, and Blocks[l][i] takes States[l][i] to
+// States[l+1][i/2].
+type Diamond struct {
+	States [][][]byte
+	Blocks [][][]byte
+}
+
+// K returns the depth of d.
+func (d Diamond) K() int {
+	return len(d.Blocks)
+}
+
+// Root returns the state every leaf leads to.
+func (d Diamond) Root() []byte {
+	return d.States[d.K()][0]
+}
+
+// Path returns the k blocks from the given leaf to the root.
+func (d Diamond) Path(leaf int) (path []byte) {
+	for l := range d.Blocks {
+		path = append(path, d.Blocks[l][leaf]...)
+		leaf /= 2
+	}
+
+	return
+}
+
+type diamondPair struct {
+	index      int
+	a, b, next []byte
+}
+
+// BuildDiamond builds a diamond structure of depth k under h.
+// Each level halves the states by colliding them in pairs, and the pairs'
+// birthday searches are shared out among NumCPU workers.
+// Cryptopals Set 7, Challenge 54
+// https://cryptopals.com/sets/7/challenges/54
+func BuildDiamond(h md.Hash, k int) (d Diamond) {
+	leaves := make([][]byte, 0, 1<<uint(k))
+	seen := make(map[string]bool)
+	for len(leaves) < 1<<uint(k) {
+		leaf := random.Bytes(h.Size())
+		if !seen[string(leaf)] {
+			seen[string(leaf)] = true
+			leaves = append(leaves, leaf)
+		}
+	}
+	d.States = [][][]byte{leaves}
+
+	numWorkers := runtime.NumCPU()
+	for l := 0; l < k; l++ {
+		states := d.States[l]
+		numPairs := len(states) / 2
+
+		jobs := make(chan int, numPairs)
+		results := make(chan diamondPair, numPairs)
+
+		for i := 0; i < numWorkers; i++ {
+			go func(jobs chan int, results chan diamondPair) {
+				for j := range jobs {
+					a, b, next := crossCollision(h, states[2*j], states[2*j+1])
+					results <- diamondPair{j, a, b, next}
+				}
+			}(jobs, results)
+		}
+
+		for j := 0; j < numPairs; j++ {
+			jobs <- j
+		}
+
+		close(jobs)
+
+		blocks := make([][]byte, len(states))
+		next := make([][]byte, numPairs)
+		for j := 0; j < numPairs; j++ {
+			pair := <-results
+			blocks[2*pair.index] = pair.a
+			blocks[2*pair.index+1] = pair.b
+			next[pair.index] = pair.next
+		}
+
+		d.Blocks = append(d.Blocks, blocks)
+		d.States = append(d.States, next)
+	}
+
+	return
+}
+
+// Prediction is a digest committed to before the message it hashes is
+// known. Any prefix of up to PrefixBlocks blocks can later be herded into a
+// message with that digest.
+// Cryptopals Set 7, Challenge 54
+// https://cryptopals.com/sets/7/challenges/54
+type Prediction struct {
+	Digest       []byte
+	PrefixBlocks int
+	h            md.Hash
+	diamond      Diamond
+}
+
+// length returns the length in bytes of every herded message: the prefix,
+// a linking block, and a path through the diamond.
+func (p Prediction) length() int {
+	return (p.PrefixBlocks + 1 + p.diamond.K()) * md.BlockSize
+}
+
+// NewPrediction builds a diamond of depth k under h, and commits to the
+// digest of messages with a prefix of prefixBlocks blocks. The length has to
+// be fixed now, because the padding that ends the hash includes it.
+// Cryptopals Set 7, Challenge 54
+// https://cryptopals.com/sets/7/challenges/54
+func NewPrediction(h md.Hash, k, prefixBlocks int) (p Prediction) {
+	p.h = h
+	p.diamond = BuildDiamond(h, k)
+	p.PrefixBlocks = prefixBlocks
+	p.Digest = h.Chain(p.diamond.Root(), md.Pad(uint64(p.length())))
+
+	return
+}
+
+// Herd turns prefix into a message with the predicted digest. The prefix is
+// padded with spaces to PrefixBlocks blocks, then a linking block is
+// searched for which lands on any leaf of the diamond, about 2^(bits-k)
+// tries.
+// Cryptopals Set 7, Challenge 54
+// https://cryptopals.com/sets/7/challenges/54
+func (p Prediction) Herd(prefix []byte) (message []byte, err error) {
+	if len(prefix) > p.PrefixBlocks*md.BlockSize {
+		return nil, fmt.Errorf("Prefix of %d bytes is longer than the predicted %d blocks", len(prefix), p.PrefixBlocks)
+	}
+	message = append([]byte{}, prefix...)
+	message = append(message, bytes.Repeat([]byte(" "), p.PrefixBlocks*md.BlockSize-len(prefix))...)
+
+	leaves := make(map[string]int)
+	for i, leaf := range p.diamond.States[0] {
+		leaves[string(leaf)] = i
+	}
+
+	state := p.h.Chain(p.h.Iv(), message)
+	for {
+		link := random.Bytes(md.BlockSize)
+		leaf, ok := leaves[string(p.h.Compress(state, link))]
+		if !ok {
+			continue
+		}
+
+		message = append(message, link...)
+
+		return append(message, p.diamond.Path(leaf)...), nil
+	}
+}
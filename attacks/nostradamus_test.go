@@ -0,0 +1,59 @@
+package attacks
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/hash/md"
+)
+
+func TestBuildDiamond(t *testing.T) {
+	h, _ := md.New(16)
+	k := 5
+
+	d := BuildDiamond(h, k)
+	if d.K() != k || len(d.States[0]) != 1<<uint(k) {
+		t.Errorf("BuildDiamond() has depth %d and %d leaves, want %d and %d", d.K(), len(d.States[0]), k, 1<<uint(k))
+	}
+
+	for leaf, state := range d.States[0] {
+		if got := h.Chain(state, d.Path(leaf)); !reflect.DeepEqual(got, d.Root()) {
+			t.Errorf("Chain(leaf %d, Diamond.Path()) = %x, want root %x", leaf, got, d.Root())
+		}
+	}
+}
+
+func TestPrediction_Herd(t *testing.T) {
+	h, _ := md.New(24)
+	p := NewPrediction(h, 8, 4)
+
+	tests := []struct {
+		name    string
+		prefix  []byte
+		wantErr bool
+	}{
+		{"results", []byte("Red Sox 7, Yankees 2; Cubs 3, Mets 1"), false},
+		{"empty", []byte{}, false},
+		{"too_long", bytes.Repeat([]byte("x"), 4*md.BlockSize+1), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := p.Herd(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Prediction.Herd() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !bytes.HasPrefix(message, tt.prefix) {
+				t.Errorf("Prediction.Herd() = %q, want prefix %q", message, tt.prefix)
+			}
+			if got := h.Sum(message); !reflect.DeepEqual(got, p.Digest) {
+				t.Errorf("Hash.Sum(Prediction.Herd()) = %x, want %x", got, p.Digest)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package attacks
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+// Rc4BiasConfig tunes Rc4BiasAttack. Zero fields take the defaults.
+type Rc4BiasConfig struct {
+	// Samples is the number of ciphertexts collected for each length of
+	// request. Default 2^24.
+	Samples int
+	// Workers is the number of goroutines encrypting at once.
+	// Default NumCPU.
+	Workers int
+	// BatchSize is the number of ciphertexts a worker tallies before
+	// handing its counts back. Default 2^14.
+	BatchSize int
+}
+
+func (config Rc4BiasConfig) withDefaults() Rc4BiasConfig {
+	if config.Samples <= 0 {
+		config.Samples = 1 << 24
+	}
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 1 << 14
+	}
+
+	return config
+}
+
+// the most likely values of the 16th and 32nd RC4 keystream bytes
+const (
+	rc4Z16Bias = 0xF0
+	rc4Z32Bias = 0xE0
+)
+
+// rc4Counts tallies the ciphertext bytes at indexes 15 and 31.
+type rc4Counts [2][256]int
+
+// Rc4BiasAttack recovers the cookie, of up to 32 bytes, which oracle
+// appends to each request before encrypting it under a fresh RC4 key.
+//
+// The 16th keystream byte is 0xF0, and the 32nd 0xE0, a little more often
+// than any other value. So a request of 15 - i bytes puts cookie byte i at
+// index 15, where the commonest ciphertext byte is most likely it XOR 0xF0,
+// and the same ciphertexts put byte i + 16 at index 31.
+// Cryptopals Set 7, Challenge 56
+// https://cryptopals.com/sets/7/challenges/56
+func Rc4BiasAttack(oracle oracle.EncryptionOracle, config Rc4BiasConfig) (cookie []byte, err error) {
+	config = config.withDefaults()
+
+	ciphertext, err := oracle.Encrypt(nil)
+	if err != nil {
+		return
+	}
+	if len(ciphertext) > 32 {
+		return nil, fmt.Errorf("Cookie of %d bytes reaches past the biased keystream bytes", len(ciphertext))
+	}
+
+	cookie = make([]byte, len(ciphertext))
+	for i := 0; i < 16 && i < len(cookie); i++ {
+		var counts rc4Counts
+		counts, err = rc4Tally(oracle, bytes.Repeat([]byte("A"), 15-i), config)
+		if err != nil {
+			return nil, err
+		}
+
+		cookie[i] = mostCommon(counts[0]) ^ rc4Z16Bias
+		if i+16 < len(cookie) {
+			cookie[i+16] = mostCommon(counts[1]) ^ rc4Z32Bias
+		}
+	}
+
+	return
+}
+
+type rc4Batch struct {
+	counts rc4Counts
+	err    error
+}
+
+// rc4Tally encrypts request config.Samples times, in batches spread across
+// config.Workers goroutines, and counts the ciphertext bytes at indexes 15
+// and 31.
+func rc4Tally(oracle oracle.EncryptionOracle, request []byte, config Rc4BiasConfig) (counts rc4Counts, err error) {
+	numBatches := (config.Samples + config.BatchSize - 1) / config.BatchSize
+
+	jobs := make(chan int, numBatches)
+	results := make(chan rc4Batch, numBatches)
+
+	for i := 0; i < config.Workers; i++ {
+		go func(jobs chan int, results chan rc4Batch) {
+			for size := range jobs {
+				var batch rc4Batch
+				for n := 0; n < size; n++ {
+					ciphertext, err := oracle.Encrypt(request)
+					if err != nil {
+						batch.err = err
+						break
+					}
+					batch.counts[0][ciphertext[15]]++
+					if len(ciphertext) > 31 {
+						batch.counts[1][ciphertext[31]]++
+					}
+				}
+				results <- batch
+			}
+		}(jobs, results)
+	}
+
+	for remaining := config.Samples; remaining > 0; remaining -= config.BatchSize {
+		if remaining < config.BatchSize {
+			jobs <- remaining
+		} else {
+			jobs <- config.BatchSize
+		}
+	}
+
+	close(jobs)
+
+	for i := 0; i < numBatches; i++ {
+		batch := <-results
+		if batch.err != nil {
+			err = batch.err
+		}
+		for z := range counts {
+			for b, n := range batch.counts[z] {
+				counts[z][b] += n
+			}
+		}
+	}
+
+	return
+}
+
+func mostCommon(counts [256]int) (b byte) {
+	for i, n := range counts {
+		if n > counts[b] {
+			b = byte(i)
+		}
+	}
+
+	return
+}
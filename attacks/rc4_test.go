@@ -0,0 +1,32 @@
+package attacks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/rc4"
+)
+
+func TestRc4BiasAttack(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes about a minute")
+	}
+
+	// two bytes only need two rounds of samples, but each needs the full
+	// default of 2^24 to pick out the bias reliably
+	cookie := []byte("OK")
+	got, err := Rc4BiasAttack(rc4.NewRc4OracleWithCookie(cookie), Rc4BiasConfig{})
+	if err != nil {
+		t.Fatalf("Rc4BiasAttack() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cookie) {
+		t.Errorf("Rc4BiasAttack() = %q, want %q", got, cookie)
+	}
+}
+
+func TestRc4BiasAttack_tooLong(t *testing.T) {
+	oracle := rc4.NewRc4OracleWithCookie(make([]byte, 33))
+	if _, err := Rc4BiasAttack(oracle, Rc4BiasConfig{}); err == nil {
+		t.Errorf("Rc4BiasAttack() error = nil, want an error for a 33-byte cookie")
+	}
+}
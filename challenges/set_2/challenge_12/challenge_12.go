@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
 	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"os"
 )
 
@@ -18,10 +19,13 @@ func main() {
 	unknownBytes, err := base64.StdEncoding.DecodeString("Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK")
 	check(err)
 
-	oracle, err := ecb.NewAesEcbOracle(unknownBytes, false)
+	ecbOracle, err := ecb.NewAesEcbOracle(unknownBytes, false)
 	check(err)
 
-	blockSize, err := attacks.AesEcbOracleDetectBlockSize(oracle)
+	meter := &oracle.Meter{}
+	metered := meter.EncryptionOracle(ecbOracle)
+
+	blockSize, err := attacks.AesEcbOracleDetectBlockSize(metered)
 	check(err)
 
 	if blockSize > 0 {
@@ -31,8 +35,14 @@ func main() {
 		os.Exit(0)
 	}
 
-	recovered, err := attacks.AesEcbOracleBreak(oracle)
+	fmt.Println("Oracle queries:", meter.Stats())
+	meter.Reset()
+
+	recovered, err := attacks.AesEcbOracleBreak(metered)
 	check(err)
 
 	fmt.Println("Recovered:", string(recovered))
+	// about 128 guesses per byte on average, at most 256
+	fmt.Println("Oracle queries:", meter.Stats())
+	fmt.Printf("Queries per recovered byte: %.1f\n", float64(meter.Queries())/float64(len(recovered)))
 }
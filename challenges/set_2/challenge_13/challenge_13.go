@@ -5,6 +5,7 @@ import (
 
 	"github.com/adavidalbertson/cryptopals/aes/ecb"
 	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
 )
 
 func check(err error) {
@@ -30,14 +31,19 @@ func main() {
 	fmt.Println("=============================================================")
 	fmt.Println()
 
-	blockSize, err := attacks.ProfileOracleDetectBlockSize(pm)
+	meter := &oracle.Meter{}
+	metered := meter.ProfileOracle(pm)
+
+	blockSize, err := attacks.ProfileOracleDetectBlockSize(metered)
 	check(err)
 
 	fmt.Println("Block size:", blockSize)
 
-	adminCiphertext, err := attacks.ProfileSpoofAdmin(pm)
+	adminCiphertext, err := attacks.ProfileSpoofAdmin(metered)
 	check(err)
 
+	fmt.Println("Oracle queries:", meter.Stats())
+
 	adminProfile, err := pm.DecryptProfile(adminCiphertext)
 	check(err)
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/adavidalbertson/cryptopals/aes/cbc"
 	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
 )
 
 func check(err error) {
@@ -13,9 +14,10 @@ func check(err error) {
 }
 
 func main() {
+	meter := &oracle.Meter{}
 	oracle := cbc.NewAesCbcOracle()
 
-	plaintext := "some data;admin=true"
+	plaintext := []byte("some data;admin=true")
 	ciphertext, err := oracle.Encrypt(plaintext)
     check(err)
 
@@ -32,8 +34,9 @@ func main() {
 	fmt.Println("=============================================================")
 	fmt.Println()
 
-	ciphertext, err = attacks.AesCbcOracleBreak(oracle)
+	ciphertext, err = attacks.AesCbcOracleBreak(meter.EncryptionOracle(oracle))
     check(err)
+	fmt.Println("Oracle queries:", meter.Stats())
 
 	success, err = oracle.Decrypt(ciphertext)
 	check(err)
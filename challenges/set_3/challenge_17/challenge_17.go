@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/adavidalbertson/cryptopals/aes/cbc"
 	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
 )
 
 func check(err error) {
@@ -13,11 +14,20 @@ func check(err error) {
 }
 
 func main() {
+	meter := &oracle.Meter{}
 	oracle := cbc.NewPaddingOracle()
 
-	plaintext, err := attacks.PaddingOracleAttack(oracle)
+	ciphertext, iv, err := oracle.Encrypt()
 	check(err)
 
+	plaintext, err := attacks.PaddingOracleAttack(meter.PaddingValidator(oracle), ciphertext, iv)
+	check(err)
+
+	// up to 256 guesses per byte, except in the first block where a guess
+	// that passes is accepted immediately
+	fmt.Println("Oracle queries:", meter.Stats())
+	fmt.Println("Upper bound:", 256*len(ciphertext))
+
 	fmt.Println(plaintext)
 	fmt.Println(string(plaintext))
 }
@@ -5,6 +5,7 @@ import (
     "encoding/binary"
     "fmt"
     "github.com/adavidalbertson/cryptopals/attacks"
+    "github.com/adavidalbertson/cryptopals/oracle"
     "github.com/adavidalbertson/cryptopals/random"
     "github.com/adavidalbertson/cryptopals/random/MT19937"
     "time"
@@ -43,8 +44,14 @@ func main() {
 
     fmt.Println("Part 2: Break MT19937 CTR oracle with known plaintext")
 
+    meter := &oracle.Meter{}
     oracle := MT19937.NewCtrOracle()
-    key = attacks.BreakMT19937CtrOracle(oracle)
+    key, err := attacks.BreakMT19937CtrOracle(meter.EncryptionOracle(&oracle))
+    if err != nil {
+        panic(err)
+    }
+    // one known-plaintext query, then 2^16 keys tried offline
+    fmt.Println("Oracle queries:", meter.Stats())
 
     fmt.Println("Found the key:", key)
 
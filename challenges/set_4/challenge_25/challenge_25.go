@@ -7,6 +7,7 @@ import (
     "fmt"
     "github.com/adavidalbertson/cryptopals/aes/ctr"
     "github.com/adavidalbertson/cryptopals/attacks"
+    "github.com/adavidalbertson/cryptopals/oracle"
     "github.com/adavidalbertson/cryptopals/random"
     "os"
 )
@@ -46,8 +47,11 @@ func main() {
 
     fmt.Println("=============================================================")
 
-    recovered, err := attacks.BreakCtrEdit(cipher, ciphertextBytes)
+    meter := &oracle.Meter{}
+    recovered, err := attacks.BreakCtrEdit(meter.EditOracle(&cipher), ciphertextBytes)
     check(err)
 
     fmt.Println(string(recovered))
+    // one query per block
+    fmt.Println("Oracle queries:", meter.Stats())
 }
@@ -0,0 +1,52 @@
+// Driver program for Cryptopals Set 4, challenge 26
+// https://cryptopals.com/sets/4/challenges/26
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	meter := &oracle.Meter{}
+	oracle := ctr.NewAesCtrOracle()
+
+	plaintext := []byte("some data;admin=true")
+	ciphertext, err := oracle.Encrypt(plaintext)
+	check(err)
+
+	success, err := oracle.Decrypt(ciphertext)
+	check(err)
+
+	if success {
+		fmt.Println("Congratulations, you are admin!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	ciphertext, err = attacks.AesCtrOracleBreak(meter.EncryptionOracle(oracle))
+	check(err)
+	fmt.Println("Oracle queries:", meter.Stats())
+
+	success, err = oracle.Decrypt(ciphertext)
+	check(err)
+
+	if success {
+		fmt.Println("Congratulations, you are admin!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
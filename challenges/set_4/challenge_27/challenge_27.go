@@ -0,0 +1,46 @@
+// Driver program for Cryptopals Set 4, challenge 27
+// https://cryptopals.com/sets/4/challenges/27
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/padding"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	meter := &oracle.Meter{}
+	oracle := cbc.NewKeyAsIvOracle()
+
+	key, err := attacks.KeyAsIvAttack(meter.EncryptionOracle(oracle), meter.AdminChecker(oracle))
+	check(err)
+	fmt.Printf("Recovered key: %x\n", key)
+	fmt.Println("Oracle queries:", meter.Stats())
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	plaintext, err := padding.Pkcs7([]byte("userdata=x;admin=true"), 16)
+	check(err)
+	token, err := cbc.Encrypt(plaintext, key, key)
+	check(err)
+
+	success, err := oracle.Decrypt(token)
+	check(err)
+
+	if success {
+		fmt.Println("Congratulations, you are admin!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,40 @@
+// Driver program for Cryptopals Set 4, challenge 28
+// https://cryptopals.com/sets/4/challenges/28
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/hash/sha1"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	mac := sha1.NewMacOracle()
+
+	message := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	tag, err := mac.Sign(message)
+	check(err)
+	fmt.Printf("%s\n%x\n", message, tag)
+
+	valid, err := mac.Verify(message, tag)
+	check(err)
+	fmt.Println("Valid:", valid)
+
+	tampered := append([]byte{}, message...)
+	tampered[len(tampered)-1] ^= 1
+	valid, err = mac.Verify(tampered, tag)
+	check(err)
+	fmt.Printf("%s\nValid: %v <-- can't change the message without the key\n", tampered, valid)
+
+	// the stand-in tag an attacker could compute without the key
+	forged := sha1.SecretPrefixMac(nil, tampered)
+	valid, err = mac.Verify(tampered, forged)
+	check(err)
+	fmt.Printf("%x\nValid: %v <-- or make a tag without it\n", forged, valid)
+}
@@ -0,0 +1,47 @@
+// Driver program for Cryptopals Set 4, challenge 30
+// https://cryptopals.com/sets/4/challenges/30
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	mac := md4.NewMacOracle()
+
+	message := []byte("comment1=cooking%20MCs;userdata=foo;comment2=%20like%20a%20pound%20of%20bacon")
+	tag, err := mac.Sign(message)
+	check(err)
+	fmt.Printf("%s\n%x\n", message, tag)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	meter := &oracle.Meter{}
+	forgedMessage, forgedTag, err := attacks.LengthExtensionAttack(attacks.Md4, meter.MacVerifier(mac), message, tag, []byte(";admin=true"), 64)
+	check(err)
+	fmt.Printf("%q\n%x\n", forgedMessage, forgedTag)
+
+	// one query per key length tried
+	fmt.Println("Oracle queries:", meter.Stats())
+
+	valid, err := mac.Verify(forgedMessage, forgedTag)
+	check(err)
+	if valid && bytes.Contains(forgedMessage, []byte(";admin=true")) {
+		fmt.Println("Congratulations, you are admin!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,55 @@
+// Driver program for Cryptopals Set 4, challenge 31
+// https://cryptopals.com/sets/4/challenges/31
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/hmac"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	server := hmac.NewServer(50 * time.Millisecond)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	check(err)
+	defer listener.Close()
+	go http.Serve(listener, server)
+
+	// keep idle connections for all the attack's workers, so it doesn't
+	// run out of ports
+	client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 1024}}
+	verifier := hmac.Client{URL: "http://" + listener.Addr().String() + "/test", HTTPClient: client}
+
+	file := []byte("foo")
+	fmt.Printf("Recovering the signature for %q from %s\n", file, verifier.URL)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	meter := &oracle.Meter{}
+	signature, err := attacks.TimingAttack(meter.MacVerifier(verifier), file, 20, attacks.TimingAttackConfig{})
+	check(err)
+	fmt.Printf("%x\n", signature)
+	fmt.Println("Oracle queries:", meter.Stats())
+
+	valid, err := verifier.Verify(file, signature)
+	check(err)
+	if valid {
+		fmt.Println("Signature accepted!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,41 @@
+// Driver program for Cryptopals Set 5, challenge 33
+// https://cryptopals.com/sets/5/challenges/33
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/dh"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	for i, group := range []dh.Group{dh.ToyGroup, dh.NistGroup} {
+		if i > 0 {
+			fmt.Println()
+			fmt.Println("=============================================================")
+			fmt.Println()
+		}
+
+		alice, err := group.GenerateKey()
+		check(err)
+		bob, err := group.GenerateKey()
+		check(err)
+
+		s1, s2 := alice.SharedSecret(bob.Public), bob.SharedSecret(alice.Public)
+		fmt.Printf("p = %x, g = %v\n", group.P, group.G)
+		fmt.Printf("Alice's secret: %x\n", s1)
+		fmt.Printf("Bob's secret:   %x\n", s2)
+
+		ciphertext, err := dh.EncryptMessage([]byte("Ice Ice Baby"), dh.Key(s1))
+		check(err)
+		message, err := dh.DecryptMessage(ciphertext, dh.Key(s2))
+		check(err)
+		fmt.Printf("Alice sends %x, Bob reads %q\n", ciphertext, message)
+	}
+}
@@ -0,0 +1,74 @@
+// Driver program for Cryptopals Set 7, challenge 49
+// https://cryptopals.com/sets/7/challenges/49
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbcmac"
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+const (
+	victim   = 1
+	attacker = 2
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// post submits a request to the bank and prints its answer.
+func post(url string, request []byte) {
+	resp, err := http.Post(url, "application/octet-stream", bytes.NewReader(request))
+	check(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	check(err)
+	fmt.Printf("%s\n%s", resp.Status, body)
+}
+
+func main() {
+	bank := cbcmac.NewBank()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	check(err)
+	defer listener.Close()
+	go http.Serve(listener, bank)
+	url := "http://" + listener.Addr().String()
+
+	// the attacker's web client only signs requests from their own account
+	client := bank.Client(attacker)
+
+	request, err := client.Request(attacker, 1000000)
+	check(err)
+	fmt.Printf("%q\n", request)
+
+	forged, err := attacks.CbcMacIvForgery(request, victim)
+	check(err)
+	fmt.Printf("%q\n", forged)
+	post(url+"/transfer", forged)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	captured, err := bank.Client(victim).RequestList([]cbcmac.Payment{{To: 3, Amount: 100}, {To: 4, Amount: 250}})
+	check(err)
+	fmt.Printf("%q\n", captured)
+
+	meter := &oracle.Meter{}
+	forged, err = attacks.CbcMacLengthExtension(captured, meter.MacSigner(client), attacker, 1000000)
+	check(err)
+	fmt.Printf("%q\n", forged)
+	fmt.Println("Oracle queries:", meter.Stats())
+	post(url+"/tx_list", forged)
+}
@@ -0,0 +1,39 @@
+// Driver program for Cryptopals Set 7, challenge 50
+// https://cryptopals.com/sets/7/challenges/50
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbcmac"
+	"github.com/adavidalbertson/cryptopals/attacks"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	original := []byte("alert('MZA who was that?');\n")
+	digest := cbcmac.Hash(original)
+	fmt.Printf("%q\n%x\n", original, digest)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	// the bridge block goes in a comment, which a newline would end
+	forged, err := attacks.CbcMacHashForgery([]byte("alert('Ayo, the Wu is back!');\n//"), digest, "\r\n")
+	check(err)
+	forgedDigest := cbcmac.Hash(forged)
+	fmt.Printf("%q\n%x\n", forged, forgedDigest)
+
+	if bytes.Equal(forgedDigest, digest) {
+		fmt.Println("Same hash, different script!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,34 @@
+// Driver program for Cryptopals Set 7, challenge 51
+// https://cryptopals.com/sets/7/challenges/51
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/compression"
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	for i, mode := range []compression.Mode{compression.Ctr, compression.Cbc} {
+		if i > 0 {
+			fmt.Println()
+			fmt.Println("=============================================================")
+			fmt.Println()
+		}
+
+		meter := &oracle.Meter{}
+		sessionID, err := attacks.CompressionOracleAttack(meter.EncryptionOracle(compression.NewCompressionOracle(mode)), []byte("sessionid="))
+		check(err)
+
+		fmt.Printf("%v: sessionid=%s\n", mode, sessionID)
+		fmt.Println("Oracle queries:", meter.Stats())
+	}
+}
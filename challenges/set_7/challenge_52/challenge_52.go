@@ -0,0 +1,39 @@
+// Driver program for Cryptopals Set 7, challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/md"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	f, err := md.New(16)
+	check(err)
+	g, err := md.New(32)
+	check(err)
+
+	start := time.Now()
+	m := attacks.JouxMulticollision(f, f.Iv(), 8)
+	fmt.Printf("%d messages colliding in the 16-bit hash in %v, all hashing to %x\n", m.Count(), time.Since(start), f.Sum(m.Message(0)))
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	c := md.Cascade{F: f, G: g}
+	start = time.Now()
+	a, b, err := attacks.CascadeCollision(c)
+	check(err)
+	fmt.Printf("%x\n%x\n", a, b)
+	fmt.Printf("Both hash to %x under the 48-bit cascade, found in %v\n", c.Sum(a), time.Since(start))
+}
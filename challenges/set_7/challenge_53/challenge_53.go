@@ -0,0 +1,45 @@
+// Driver program for Cryptopals Set 7, challenge 53
+// https://cryptopals.com/sets/7/challenges/53
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/md"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	h, err := md.New(32)
+	check(err)
+
+	// 2^16 blocks: a second preimage should cost about 2^16 tries, not 2^32
+	target := random.Bytes((1 << 16) * md.BlockSize)
+	digest := h.Sum(target)
+	fmt.Printf("Target: %d blocks, hash %x\n", len(target)/md.BlockSize, digest)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	start := time.Now()
+	forged, prefixBlocks, err := attacks.SecondPreimage(h, target)
+	check(err)
+	fmt.Printf("Expandable message of %d blocks, bridge block, then %d blocks of the target\n", prefixBlocks, len(target)/md.BlockSize-prefixBlocks-1)
+	fmt.Printf("Forged: %d blocks, hash %x, found in %v\n", len(forged)/md.BlockSize, h.Sum(forged), time.Since(start))
+
+	if bytes.Equal(h.Sum(forged), digest) && !bytes.Equal(forged, target) {
+		fmt.Println("Second preimage found!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,46 @@
+// Driver program for Cryptopals Set 7, challenge 54
+// https://cryptopals.com/sets/7/challenges/54
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/md"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	h, err := md.New(24)
+	check(err)
+
+	// a diamond of 2^8 leaves makes the linking block cost about 2^16 tries,
+	// not 2^24
+	start := time.Now()
+	prediction := attacks.NewPrediction(h, 8, 4)
+	fmt.Printf("Prediction: %x, committed in %v\n", prediction.Digest, time.Since(start))
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	results := []byte("Red Sox 7, Yankees 2; Cubs 3, Mets 1; Giants 5, Dodgers 4")
+	start = time.Now()
+	message, err := prediction.Herd(results)
+	check(err)
+	fmt.Printf("Message: %q\n", message[:prediction.PrefixBlocks*md.BlockSize])
+	fmt.Printf("Followed by %d blocks of glue, hash %x, found in %v\n", len(message)/md.BlockSize-prediction.PrefixBlocks, h.Sum(message), time.Since(start))
+
+	if bytes.HasPrefix(message, results) && bytes.Equal(h.Sum(message), prediction.Digest) {
+		fmt.Println("Prediction came true!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,37 @@
+// Driver program for Cryptopals Set 7, challenge 55
+// https://cryptopals.com/sets/7/challenges/55
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/hash/md4"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	a, b, stats, err := attacks.Md4Collision(1 << 32)
+	check(err)
+	fmt.Printf("%x\n%x\n", a, b)
+	fmt.Println("Found in", stats)
+
+	fmt.Println()
+	fmt.Println("=============================================================")
+	fmt.Println()
+
+	sumA, sumB := md4.Sum(a), md4.Sum(b)
+	fmt.Printf("MD4: %x\n     %x\n", sumA, sumB)
+
+	if !bytes.Equal(a, b) && sumA == sumB {
+		fmt.Println("Collision found!")
+	} else {
+		fmt.Println("Nope, try again")
+	}
+}
@@ -0,0 +1,27 @@
+// Driver program for Cryptopals Set 7, challenge 56
+// https://cryptopals.com/sets/7/challenges/56
+package main
+
+import (
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/rc4"
+)
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+func main() {
+	// 2^24 ciphertexts for each of 16 request lengths: a few minutes
+	meter := &oracle.Meter{}
+	cookie, err := attacks.Rc4BiasAttack(meter.EncryptionOracle(rc4.NewRc4Oracle()), attacks.Rc4BiasConfig{})
+	check(err)
+
+	fmt.Printf("Cookie: %q\n", cookie)
+	fmt.Println("Oracle queries:", meter.Stats())
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/xor"
+)
+
+var padders = map[string]padding.Padder{
+	"pkcs7":    padding.Pkcs7Padder{},
+	"x923":     padding.AnsiX923Padder{},
+	"iso10126": padding.Iso10126Padder{},
+	"iso7816":  padding.Iso7816Padder{},
+	"zero":     padding.ZeroPadder{},
+}
+
+var counterFormats = map[string]ctr.CounterFormat{
+	"le64":  ctr.LittleEndian64,
+	"be64":  ctr.BigEndian64,
+	"be128": ctr.BigEndian128,
+}
+
+// padderFor looks up a padding scheme by name. "none" returns nil.
+func padderFor(name string) (padding.Padder, error) {
+	if name == "none" {
+		return nil, nil
+	}
+
+	padder, ok := padders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown padding scheme: %s", name)
+	}
+
+	return padder, nil
+}
+
+// aesFlags are the flags shared by enc and dec.
+type aesFlags struct {
+	ioFlags
+	mode, key, iv, nonce, paramform, counter, padding string
+}
+
+func parseAesFlags(name, summary string, args []string) (*aesFlags, error) {
+	f := &aesFlags{}
+	fs := newFlagSet(name, summary)
+	f.ioFlags.register(fs)
+	fs.StringVar(&f.mode, "mode", "cbc", "mode of operation: ecb, cbc, or ctr")
+	fs.StringVar(&f.key, "key", "", "AES key (16, 24, or 32 bytes)")
+	fs.StringVar(&f.iv, "iv", "", "CBC initialization vector (default all zeros)")
+	fs.StringVar(&f.nonce, "nonce", "", "CTR nonce (default all zeros)")
+	fs.StringVar(&f.paramform, "paramform", "hex", "encoding of -key, -iv, and -nonce: raw, hex, or base64")
+	fs.StringVar(&f.counter, "counter", "le64", "CTR counter format: le64, be64, or be128")
+	fs.StringVar(&f.padding, "padding", "pkcs7", "ECB and CBC padding: pkcs7, x923, iso10126, iso7816, zero, or none")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if f.key == "" {
+		return nil, fmt.Errorf("-key is required")
+	}
+
+	return f, nil
+}
+
+// crypt runs AES in the chosen mode. Padding is added before encryption
+// and removed after decryption.
+func (f *aesFlags) crypt(input []byte, decrypt bool) (output []byte, err error) {
+	key, err := parseParam("key", f.key, f.paramform)
+	if err != nil {
+		return
+	}
+
+	if f.mode == "ctr" {
+		format, ok := counterFormats[f.counter]
+		if !ok {
+			return nil, fmt.Errorf("unknown counter format: %s", f.counter)
+		}
+
+		nonce, err := parseParam("nonce", f.nonce, f.paramform)
+		if err != nil {
+			return nil, err
+		}
+
+		cipher, err := ctr.NewAesCtrCipherWithFormat(key, nonce, format)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.Encrypt(input)
+	}
+
+	padder, err := padderFor(f.padding)
+	if err != nil {
+		return
+	}
+
+	if !decrypt && padder != nil {
+		input, err = padder.Pad(input, 16)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(input)%16 != 0 {
+		return nil, fmt.Errorf("input length %d is not a multiple of the block size", len(input))
+	}
+
+	switch f.mode {
+	case "ecb":
+		if decrypt {
+			output, err = ecb.Decrypt(input, key)
+		} else {
+			output, err = ecb.Encrypt(input, key)
+		}
+	case "cbc":
+		iv, err := parseParam("iv", f.iv, f.paramform)
+		if err != nil {
+			return nil, err
+		}
+
+		if decrypt {
+			output, err = cbc.Decrypt(input, key, iv)
+		} else {
+			output, err = cbc.Encrypt(input, key, iv)
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown mode: %s", f.mode)
+	}
+	if err != nil {
+		return
+	}
+
+	if decrypt && padder != nil {
+		output, err = padder.Unpad(output)
+	}
+
+	return
+}
+
+func runAes(name, summary string, decrypt bool, args []string, stdin io.Reader, stdout io.Writer) error {
+	f, err := parseAesFlags(name, summary, args)
+	if err != nil {
+		return err
+	}
+
+	input, err := f.read(stdin)
+	if err != nil {
+		return err
+	}
+
+	output, err := f.crypt(input, decrypt)
+	if err != nil {
+		return err
+	}
+
+	return f.write(stdout, output)
+}
+
+func runEnc(args []string, stdin io.Reader, stdout io.Writer) error {
+	return runAes("enc", "Encrypt with AES in ECB, CBC, or CTR mode.", false, args, stdin, stdout)
+}
+
+func runDec(args []string, stdin io.Reader, stdout io.Writer) error {
+	return runAes("dec", "Decrypt with AES in ECB, CBC, or CTR mode.", true, args, stdin, stdout)
+}
+
+func runXor(args []string, stdin io.Reader, stdout io.Writer) error {
+	var f ioFlags
+	var key, paramform string
+	fs := newFlagSet("xor", "Apply repeating-key XOR. Encryption and decryption are the same.")
+	f.register(fs)
+	fs.StringVar(&key, "key", "", "XOR key")
+	fs.StringVar(&paramform, "paramform", "raw", "encoding of -key: raw, hex, or base64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	keyBytes, err := parseParam("key", key, paramform)
+	if err != nil {
+		return err
+	}
+	if len(keyBytes) == 0 {
+		return fmt.Errorf("-key is required")
+	}
+
+	input, err := f.read(stdin)
+	if err != nil {
+		return err
+	}
+
+	return f.write(stdout, xor.VigenereXorBytes(input, keyBytes))
+}
+
+func runPad(args []string, stdin io.Reader, stdout io.Writer) error {
+	var f ioFlags
+	var scheme string
+	var blockSize int
+	fs := newFlagSet("pad", "Pad the input to a multiple of the block size.")
+	f.register(fs)
+	fs.StringVar(&scheme, "padding", "pkcs7", "padding scheme: pkcs7, x923, iso10126, iso7816, or zero")
+	fs.IntVar(&blockSize, "block", 16, "block size in bytes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	padder, err := padderFor(scheme)
+	if err != nil || padder == nil {
+		return fmt.Errorf("unknown padding scheme: %s", scheme)
+	}
+
+	input, err := f.read(stdin)
+	if err != nil {
+		return err
+	}
+
+	padded, err := padder.Pad(input, blockSize)
+	if err != nil {
+		return err
+	}
+
+	return f.write(stdout, padded)
+}
+
+func runUnpad(args []string, stdin io.Reader, stdout io.Writer) error {
+	var f ioFlags
+	var scheme string
+	fs := newFlagSet("unpad", "Remove padding from the input.")
+	f.register(fs)
+	fs.StringVar(&scheme, "padding", "pkcs7", "padding scheme: pkcs7, x923, iso10126, iso7816, or zero")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	padder, err := padderFor(scheme)
+	if err != nil || padder == nil {
+		return fmt.Errorf("unknown padding scheme: %s", scheme)
+	}
+
+	input, err := f.read(stdin)
+	if err != nil {
+		return err
+	}
+
+	unpadded, err := padder.Unpad(input)
+	if err != nil {
+		return err
+	}
+
+	return f.write(stdout, unpadded)
+}
+
+func runEncode(args []string, stdin io.Reader, stdout io.Writer) error {
+	var f ioFlags
+	fs := newFlagSet("encode", "Convert the input from one encoding to another.")
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	input, err := f.read(stdin)
+	if err != nil {
+		return err
+	}
+
+	return f.write(stdout, input)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/adavidalbertson/cryptopals/fileutils"
+)
+
+// ioFlags are the input and output flags shared by every command.
+type ioFlags struct {
+	in, out         string
+	inform, outform string
+}
+
+func (f *ioFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.in, "in", "-", "input file, or - for stdin")
+	fs.StringVar(&f.out, "out", "-", "output file, or - for stdout")
+	fs.StringVar(&f.inform, "inform", "raw", "input encoding: raw, hex, or base64")
+	fs.StringVar(&f.outform, "outform", "raw", "output encoding: raw, hex, or base64")
+}
+
+// decoderFor returns the decode function for an encoding name, in the
+// same form fileutils uses.
+func decoderFor(encoding string) (func(string) ([]byte, error), error) {
+	switch encoding {
+	case "raw":
+		return fileutils.Identity, nil
+	case "hex":
+		return hex.DecodeString, nil
+	case "base64":
+		return base64.StdEncoding.DecodeString, nil
+	}
+
+	return nil, fmt.Errorf("unknown encoding: %s", encoding)
+}
+
+// decode decodes s, ignoring whitespace for the text encodings so that
+// wrapped files like the challenge inputs can be read directly.
+func decode(s, encoding string) ([]byte, error) {
+	decoder, err := decoderFor(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoding != "raw" {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	return decoder(s)
+}
+
+// encode encodes b, adding a trailing newline for the text encodings.
+func encode(b []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "raw":
+		return b, nil
+	case "hex":
+		return []byte(hex.EncodeToString(b) + "\n"), nil
+	case "base64":
+		return []byte(base64.StdEncoding.EncodeToString(b) + "\n"), nil
+	}
+
+	return nil, fmt.Errorf("unknown encoding: %s", encoding)
+}
+
+func (f *ioFlags) read(stdin io.Reader) ([]byte, error) {
+	var raw []byte
+	var err error
+	if f.in == "-" {
+		raw, err = io.ReadAll(stdin)
+	} else {
+		raw, err = os.ReadFile(f.in)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(string(raw), f.inform)
+}
+
+func (f *ioFlags) write(stdout io.Writer, b []byte) error {
+	encoded, err := encode(b, f.outform)
+	if err != nil {
+		return err
+	}
+
+	if f.out == "-" {
+		_, err = stdout.Write(encoded)
+		return err
+	}
+
+	return os.WriteFile(f.out, encoded, 0644)
+}
+
+// parseParam decodes a key, iv, or nonce given on the command line.
+// An empty value decodes to nil, so library defaults apply.
+func parseParam(name, value, encoding string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	b, err := decode(value, encoding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -%s: %v", name, err)
+	}
+
+	return b, nil
+}
+
+// newFlagSet returns a flag set which reports errors instead of exiting.
+func newFlagSet(name, summary string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: cryptopals %s [flags]\n\n%s\n\nFlags:\n", name, summary)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
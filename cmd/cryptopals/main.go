@@ -0,0 +1,75 @@
+// Command cryptopals encrypts, decrypts, pads, and encodes data using the
+// packages in this repository, so one-off experiments don't need a main.go.
+//
+// Usage:
+//
+//	cryptopals <command> [flags]
+//
+// Commands:
+//
+//	enc     encrypt with AES in ECB, CBC, or CTR mode
+//	dec     decrypt with AES in ECB, CBC, or CTR mode
+//	xor     apply repeating-key XOR
+//	pad     pad to a multiple of the block size
+//	unpad   remove padding
+//	encode  convert between raw, hex, and base64
+//	serve   serve the challenge oracles over HTTP
+//
+// Run "cryptopals <command> -h" for the flags of each command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name, summary string
+	run           func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"enc", "encrypt with AES in ECB, CBC, or CTR mode", runEnc},
+	{"dec", "decrypt with AES in ECB, CBC, or CTR mode", runDec},
+	{"xor", "apply repeating-key XOR", runXor},
+	{"pad", "pad to a multiple of the block size", runPad},
+	{"unpad", "remove padding", runUnpad},
+	{"encode", "convert between raw, hex, and base64", runEncode},
+	{"serve", "serve the challenge oracles over HTTP", runServe},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: cryptopals <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s%s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "cryptopals <command> -h" for the flags of each command.`)
+}
+
+// run dispatches to the named command.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return fmt.Errorf("no command given")
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdin, stdout)
+		}
+	}
+
+	usage(os.Stderr)
+	return fmt.Errorf("unknown command: %s", args[0])
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "cryptopals:", err)
+		os.Exit(1)
+	}
+}
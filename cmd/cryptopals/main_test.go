@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{
+			"enc_cbc",
+			[]string{"enc", "-mode", "cbc", "-key", "59454c4c4f57205355424d4152494e45", "-outform", "hex"},
+			"hello world",
+			"7f1b7a01ec0dfd1fc1421c6a7034e16f\n",
+			false,
+		},
+		{
+			"dec_cbc",
+			[]string{"dec", "-mode", "cbc", "-key", "59454c4c4f57205355424d4152494e45", "-inform", "hex"},
+			"7f1b7a01ec0dfd1fc1421c6a7034e16f\n",
+			"hello world",
+			false,
+		},
+		{
+			"dec_ctr_challenge_18",
+			[]string{"dec", "-mode", "ctr", "-key", "YELLOW SUBMARINE", "-paramform", "raw", "-inform", "base64"},
+			"L77na/nrFsKvynd6HzOoG7GHTLXsTVu9qvY/2syLXzhPweyyMTJULu/6/kXX0KSvoOLSFQ==",
+			"Yo, VIP Let's kick it Ice, Ice, baby Ice, Ice, baby ",
+			false,
+		},
+		{
+			"xor_challenge_5",
+			[]string{"xor", "-key", "ICE", "-outform", "hex"},
+			"Burning 'em, if you ain't quick and nimble",
+			"0b3637272a2b2e63622c2e69692a23693a2a3c6324202d623d63343c2a26226324272765272a282b2f20\n",
+			false,
+		},
+		{"pad", []string{"pad", "-block", "20", "-outform", "hex"}, "YELLOW SUBMARINE", "59454c4c4f57205355424d4152494e4504040404\n", false},
+		{"unpad", []string{"unpad", "-inform", "hex"}, "59454c4c4f57205355424d4152494e4504040404", "YELLOW SUBMARINE", false},
+		{"unpad_invalid", []string{"unpad", "-inform", "hex"}, "59454c4c4f57205355424d4152494e4504040405", "", true},
+		{"encode", []string{"encode", "-inform", "hex", "-outform", "base64"}, "49276d206b696c6c696e67", "SSdtIGtpbGxpbmc=\n", false},
+		{"no_padding_partial_block", []string{"enc", "-mode", "ecb", "-key", "00000000000000000000000000000000", "-padding", "none"}, "short", "", true},
+		{"missing_key", []string{"enc"}, "", "", true},
+		{"unknown_command", []string{"bogus"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			err := run(tt.args, strings.NewReader(tt.stdin), &stdout)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := stdout.String(); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_labServer(t *testing.T) {
+	s, endpoints, err := labServer()
+	if err != nil {
+		t.Errorf("labServer() error = %v", err)
+		return
+	}
+
+	for _, e := range endpoints {
+		req := httptest.NewRequest(http.MethodGet, e.path, nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("GET %s status = %v, want %v", e.path, w.Code, http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/compression"
+	"github.com/adavidalbertson/cryptopals/oracle/oraclehttp"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// challenge12Suffix is the unknown string from Cryptopals Set 2, Challenge 12.
+const challenge12Suffix = "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK"
+
+// endpoint is one oracle served by labServer: register adds its handler to
+// a server at path.
+type endpoint struct {
+	path        string
+	description string
+	register    func(s *oraclehttp.Server, path string)
+}
+
+// labServer returns a server with freshly keyed challenge oracles, and the
+// endpoints it serves.
+func labServer() (*oraclehttp.Server, []endpoint, error) {
+	suffix, err := base64.StdEncoding.DecodeString(challenge12Suffix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ecbOracle, err := ecb.NewAesEcbOracle(suffix, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctrCipher, err := ctr.NewAesCtrCipher(random.Bytes(16), random.Bytes(8))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cbcOracle := cbc.NewAesCbcOracle()
+	keyAsIvOracle := cbc.NewKeyAsIvOracle()
+	ctrOracle := ctr.NewAesCtrOracle()
+	paddingOracle := cbc.NewPaddingOracle()
+	compressionCtr := compression.NewCompressionOracle(compression.Ctr)
+	compressionCbc := compression.NewCompressionOracle(compression.Cbc)
+
+	endpoints := []endpoint{
+		{"/ecb/encrypt", "ECB oracle with random prefix and secret suffix (Challenges 12 and 14)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, ecbOracle) }},
+		{"/ecb/profile", "encrypted user profiles (Challenge 13)",
+			func(s *oraclehttp.Server, path string) { s.HandleProfileOracle(path, ecb.NewProfileMaker()) }},
+		{"/cbc/encrypt", "CBC user tokens (Challenge 16)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, cbcOracle) }},
+		{"/cbc/admin", "checks CBC user tokens for admin=true (Challenge 16)",
+			func(s *oraclehttp.Server, path string) { s.HandleAdminChecker(path, cbcOracle) }},
+		{"/cbc/ciphertext", "ciphertext and iv for the padding oracle (Challenge 17)",
+			func(s *oraclehttp.Server, path string) { s.HandleCiphertextSource(path, paddingOracle) }},
+		{"/cbc/validate", "CBC padding oracle (Challenge 17)",
+			func(s *oraclehttp.Server, path string) { s.HandlePaddingValidator(path, paddingOracle) }},
+		{"/cbc/keyiv/encrypt", "CBC user tokens, key used as iv (Challenge 27)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, keyAsIvOracle) }},
+		{"/cbc/keyiv/admin", "checks key-as-iv tokens, rejecting high-ASCII plaintext (Challenge 27)",
+			func(s *oraclehttp.Server, path string) { s.HandleAdminChecker(path, keyAsIvOracle) }},
+		{"/ctr/edit", "CTR edit function, key unknown (Challenge 25)",
+			func(s *oraclehttp.Server, path string) { s.HandleEditOracle(path, &ctrCipher) }},
+		{"/ctr/encrypt", "CTR user tokens (Challenge 26)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, ctrOracle) }},
+		{"/ctr/admin", "checks CTR user tokens for admin=true (Challenge 26)",
+			func(s *oraclehttp.Server, path string) { s.HandleAdminChecker(path, ctrOracle) }},
+		{"/compression/ctr", "compressed, CTR encrypted requests with a session cookie (Challenge 51)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, compressionCtr) }},
+		{"/compression/cbc", "compressed, CBC encrypted requests with a session cookie (Challenge 51)",
+			func(s *oraclehttp.Server, path string) { s.HandleEncryptionOracle(path, compressionCbc) }},
+	}
+
+	s := oraclehttp.NewServer()
+	for _, e := range endpoints {
+		e.register(s, e.path)
+	}
+
+	return s, endpoints, nil
+}
+
+func runServe(args []string, stdin io.Reader, stdout io.Writer) error {
+	var addr string
+	fs := newFlagSet("serve", "Serve the challenge oracles over HTTP, for attacking with oraclehttp clients.")
+	fs.StringVar(&addr, "addr", "localhost:8080", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, endpoints, err := labServer()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Serving oracles on http://%s\n", addr)
+	for _, e := range endpoints {
+		fmt.Fprintf(stdout, "  POST %-18s %s\n", e.path, e.description)
+	}
+
+	return http.ListenAndServe(addr, s)
+}
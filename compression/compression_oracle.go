@@ -0,0 +1,107 @@
+// Package compression implements an oracle that compresses, then encrypts,
+// a request carrying a secret, so that the ciphertext length leaks how well
+// attacker-chosen content compresses against the secret.
+// Cryptopals Set 7, Challenge 51
+// https://cryptopals.com/sets/7/challenges/51
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// Mode is the cipher a CompressionOracle encrypts with.
+type Mode int
+
+const (
+	// Ctr is a stream cipher, so the ciphertext is exactly as long as the
+	// compressed request.
+	Ctr Mode = iota
+	// Cbc pads to whole blocks, hiding small changes in length.
+	Cbc
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Ctr:
+		return "CTR"
+	case Cbc:
+		return "CBC"
+	}
+
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// CompressionOracle formats attacker content into a request with a secret
+// session cookie, compresses it, and encrypts it under a fresh random key
+// each time.
+// Cryptopals Set 7, Challenge 51
+// https://cryptopals.com/sets/7/challenges/51
+type CompressionOracle struct {
+	mode      Mode
+	sessionID string
+}
+
+var _ oracle.EncryptionOracle = CompressionOracle{}
+
+// NewCompressionOracle returns a CompressionOracle with the session ID given
+// in Challenge 51.
+func NewCompressionOracle(mode Mode) CompressionOracle {
+	return NewCompressionOracleWithSession(mode, "TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=")
+}
+
+// NewCompressionOracleWithSession is like NewCompressionOracle, but uses the
+// given session ID.
+func NewCompressionOracleWithSession(mode Mode, sessionID string) CompressionOracle {
+	return CompressionOracle{mode, sessionID}
+}
+
+// FormatRequest returns the request which carries content.
+func (oracle CompressionOracle) FormatRequest(content []byte) []byte {
+	request := fmt.Sprintf("POST / HTTP/1.1\nHost: hapless.com\nCookie: sessionid=%s\nContent-Length: %d\n", oracle.sessionID, len(content))
+
+	return append([]byte(request), content...)
+}
+
+// Encrypt formats, compresses, and encrypts a request carrying content.
+// Only the length of the result is meant to be of use to an attacker.
+// Cryptopals Set 7, Challenge 51
+// https://cryptopals.com/sets/7/challenges/51
+func (oracle CompressionOracle) Encrypt(content []byte) (ciphertext []byte, err error) {
+	var compressed bytes.Buffer
+	w, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if err != nil {
+		return
+	}
+	w.Write(oracle.FormatRequest(content))
+	if err = w.Close(); err != nil {
+		return
+	}
+
+	key := random.Bytes(16)
+	switch oracle.mode {
+	case Ctr:
+		var cipher ctr.AesCtrCipher
+		cipher, err = ctr.NewAesCtrCipher(key, random.Bytes(8))
+		if err != nil {
+			return
+		}
+		return cipher.Encrypt(compressed.Bytes())
+	case Cbc:
+		var padded []byte
+		padded, err = padding.Pkcs7(compressed.Bytes(), 16)
+		if err != nil {
+			return
+		}
+		return cbc.Encrypt(padded, key, random.Bytes(16))
+	}
+
+	return nil, fmt.Errorf("Unknown mode: %v", oracle.mode)
+}
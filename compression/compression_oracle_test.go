@@ -0,0 +1,47 @@
+package compression
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompressionOracle_FormatRequest(t *testing.T) {
+	oracle := NewCompressionOracleWithSession(Ctr, "c2VjcmV0")
+	want := "POST / HTTP/1.1\nHost: hapless.com\nCookie: sessionid=c2VjcmV0\nContent-Length: 5\nhello"
+	if got := string(oracle.FormatRequest([]byte("hello"))); got != want {
+		t.Errorf("CompressionOracle.FormatRequest() = %q, want %q", got, want)
+	}
+}
+
+func TestCompressionOracle_Encrypt(t *testing.T) {
+	tests := []struct {
+		name string
+		mode Mode
+	}{
+		{"ctr", Ctr},
+		{"cbc", Cbc},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oracle := NewCompressionOracle(tt.mode)
+
+			matching, err := oracle.Encrypt([]byte(strings.Repeat("sessionid=TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=", 4)))
+			if err != nil {
+				t.Errorf("CompressionOracle.Encrypt() error = %v", err)
+				return
+			}
+			other, err := oracle.Encrypt([]byte(strings.Repeat("sessionid=R3Vlc3Mgd2hhdCB0aGUgc2Vzc2lvbiBpcz8/Pz8/Pz8=", 4)))
+			if err != nil {
+				t.Errorf("CompressionOracle.Encrypt() error = %v", err)
+				return
+			}
+
+			if len(matching) >= len(other) {
+				t.Errorf("CompressionOracle.Encrypt() length %d for the session, want less than %d", len(matching), len(other))
+			}
+			if tt.mode == Cbc && len(matching)%16 != 0 {
+				t.Errorf("CompressionOracle.Encrypt() length %d, want whole blocks", len(matching))
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Package dh implements finite-field Diffie–Hellman key exchange over
+// math/big, and AES-CBC messages under a key derived from the shared
+// secret, as a building block for the protocols of Set 5.
+// Cryptopals Set 5, Challenge 33
+// https://cryptopals.com/sets/5/challenges/33
+package dh
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// Group is a prime modulus P and a generator G.
+type Group struct {
+	P, G *big.Int
+}
+
+// NistGroup is the 1536-bit MODP group from RFC 3526, with generator 2.
+var NistGroup = Group{
+	P: mustParseHex("ffffffffffffffffc90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74" +
+		"020bbea63b139b22514a08798e3404ddef9519b3cd3a431b302b0a6df25f1437" +
+		"4fe1356d6d51c245e485b576625e7ec6f44c42e9a637ed6b0bff5cb6f406b7ed" +
+		"ee386bfb5a899fa5ae9f24117c4b1fe649286651ece45b3dc2007cb8a163bf05" +
+		"98da48361c55d39a69163fa8fd24cf5f83655d23dca3ad961c62f356208552bb" +
+		"9ed529077096966d670c354e4abc9804f1746c08ca237327ffffffffffffffff"),
+	G: big.NewInt(2),
+}
+
+// ToyGroup is the tiny group from the start of Challenge 33, p = 37 and
+// g = 5, small enough to check by hand.
+var ToyGroup = Group{P: big.NewInt(37), G: big.NewInt(5)}
+
+// SmallGroup is p = 2^31 - 1 with the primitive root g = 7: big enough that
+// keys rarely repeat, small enough to brute force.
+var SmallGroup = Group{P: big.NewInt(1<<31 - 1), G: big.NewInt(7)}
+
+func mustParseHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("dh: invalid hex constant")
+	}
+
+	return n
+}
+
+// KeyPair is a private exponent and the public value G^Private mod P.
+type KeyPair struct {
+	Group   Group
+	Private *big.Int
+	Public  *big.Int
+}
+
+// GenerateKey picks a random private exponent from 1 to P - 1.
+// Cryptopals Set 5, Challenge 33
+// https://cryptopals.com/sets/5/challenges/33
+func (group Group) GenerateKey() (key KeyPair, err error) {
+	if group.P == nil || group.G == nil || group.P.Cmp(big.NewInt(2)) < 0 {
+		return key, fmt.Errorf("Group needs a modulus of at least 2 and a generator")
+	}
+
+	// extra random bytes make the reduction mod P - 1 close to uniform
+	pMinus1 := new(big.Int).Sub(group.P, big.NewInt(1))
+	n := new(big.Int).SetBytes(random.Bytes(len(group.P.Bytes()) + 8))
+	key.Private = n.Mod(n, pMinus1).Add(n, big.NewInt(1))
+	key.Public = new(big.Int).Exp(group.G, key.Private, group.P)
+	key.Group = group
+
+	return
+}
+
+// SharedSecret returns peerPublic^Private mod P. The peer's value is not
+// checked, so a man in the middle can force the secret, as the later
+// challenges of Set 5 do.
+// Cryptopals Set 5, Challenge 33
+// https://cryptopals.com/sets/5/challenges/33
+func (key KeyPair) SharedSecret(peerPublic *big.Int) *big.Int {
+	return new(big.Int).Exp(peerPublic, key.Private, key.Group.P)
+}
@@ -0,0 +1,83 @@
+package dh
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   Group
+		bitLen  int
+		factors []int64 // prime factors of P - 1, to check G generates it all
+	}{
+		{"nist", NistGroup, 1536, nil},
+		{"toy", ToyGroup, 6, []int64{2, 3}},
+		{"small", SmallGroup, 31, []int64{2, 3, 7, 11, 31, 151, 331}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.group.P.ProbablyPrime(20) {
+				t.Errorf("P = %v, want a prime", tt.group.P)
+			}
+			if got := tt.group.P.BitLen(); got != tt.bitLen {
+				t.Errorf("P.BitLen() = %d, want %d", got, tt.bitLen)
+			}
+
+			pMinus1 := new(big.Int).Sub(tt.group.P, big.NewInt(1))
+			for _, q := range tt.factors {
+				e := new(big.Int).Div(pMinus1, big.NewInt(q))
+				if new(big.Int).Exp(tt.group.G, e, tt.group.P).Cmp(big.NewInt(1)) == 0 {
+					t.Errorf("G^((P-1)/%d) = 1, want G to be a primitive root", q)
+				}
+			}
+		})
+	}
+}
+
+func TestGroup_GenerateKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   Group
+		wantErr bool
+	}{
+		{"nist", NistGroup, false},
+		{"toy", ToyGroup, false},
+		{"small", SmallGroup, false},
+		{"empty", Group{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alice, err := tt.group.GenerateKey()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Group.GenerateKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			bob, err := tt.group.GenerateKey()
+			if err != nil {
+				t.Fatalf("Group.GenerateKey() error = %v", err)
+			}
+
+			if alice.Private.Sign() <= 0 || alice.Private.Cmp(tt.group.P) >= 0 {
+				t.Errorf("Group.GenerateKey() private = %v, want from 1 to P - 1", alice.Private)
+			}
+
+			s1, s2 := alice.SharedSecret(bob.Public), bob.SharedSecret(alice.Public)
+			if s1.Cmp(s2) != 0 {
+				t.Errorf("KeyPair.SharedSecret() = %v and %v, want equal", s1, s2)
+			}
+		})
+	}
+}
+
+func TestKeyPair_SharedSecret(t *testing.T) {
+	// worked by hand: 5^4 = 625 = 33 mod 37, and 33^3 = 35937 = 10 mod 37
+	key := KeyPair{ToyGroup, big.NewInt(3), big.NewInt(14)}
+	if got := key.SharedSecret(big.NewInt(33)); got.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("KeyPair.SharedSecret() = %v, want 10", got)
+	}
+}
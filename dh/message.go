@@ -0,0 +1,61 @@
+package dh
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/hash/sha1"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// KeySize is the size in bytes of the AES key derived from a shared secret.
+const KeySize = 16
+
+// Key derives an AES-128 key from a shared secret: the first 16 bytes of
+// the SHA-1 of its big-endian bytes.
+// Cryptopals Set 5, Challenge 33
+// https://cryptopals.com/sets/5/challenges/33
+func Key(secret *big.Int) []byte {
+	sum := sha1.Sum(secret.Bytes())
+
+	return sum[:KeySize]
+}
+
+// EncryptMessage pads message with PKCS#7 and encrypts it with AES-CBC
+// under key and a random IV, which is appended to the ciphertext.
+// Cryptopals Set 5, Challenge 34
+// https://cryptopals.com/sets/5/challenges/34
+func EncryptMessage(message, key []byte) (ciphertext []byte, err error) {
+	padded, err := padding.Pkcs7(append([]byte{}, message...), 16)
+	if err != nil {
+		return
+	}
+
+	iv := random.Bytes(16)
+	ciphertext, err = cbc.Encrypt(padded, key, iv)
+	if err != nil {
+		return
+	}
+
+	return append(ciphertext, iv...), nil
+}
+
+// DecryptMessage reverses EncryptMessage, taking the IV from the end of
+// ciphertext.
+// Cryptopals Set 5, Challenge 34
+// https://cryptopals.com/sets/5/challenges/34
+func DecryptMessage(ciphertext, key []byte) (message []byte, err error) {
+	if len(ciphertext) < 32 {
+		return nil, fmt.Errorf("Message of %d bytes is too short to hold a block and an IV", len(ciphertext))
+	}
+
+	body, iv := ciphertext[:len(ciphertext)-16], ciphertext[len(ciphertext)-16:]
+	padded, err := cbc.Decrypt(body, key, iv)
+	if err != nil {
+		return
+	}
+
+	return padding.Pkcs7Unpad(padded)
+}
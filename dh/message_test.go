@@ -0,0 +1,69 @@
+package dh
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	// SHA-1("\x01") = bf8b4530d8d246dd74ac53a13471bba17941dff7
+	want, _ := hex.DecodeString("bf8b4530d8d246dd74ac53a13471bba1")
+	if got := Key(big.NewInt(1)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Key() = %x, want %x", got, want)
+	}
+}
+
+func TestEncryptMessage(t *testing.T) {
+	key := Key(big.NewInt(12345))
+	tests := []struct {
+		name    string
+		message []byte
+	}{
+		{"empty", []byte{}},
+		{"short", []byte("hello")},
+		{"block", []byte("YELLOW SUBMARINE")},
+		{"long", []byte("Ice Ice Baby, too cold, too cold")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertext, err := EncryptMessage(tt.message, key)
+			if err != nil {
+				t.Fatalf("EncryptMessage() error = %v", err)
+			}
+			if want := (len(tt.message)/16 + 2) * 16; len(ciphertext) != want {
+				t.Errorf("EncryptMessage() length = %d, want %d", len(ciphertext), want)
+			}
+
+			got, err := DecryptMessage(ciphertext, key)
+			if err != nil {
+				t.Fatalf("DecryptMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.message) {
+				t.Errorf("DecryptMessage() = %q, want %q", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestDecryptMessage_invalid(t *testing.T) {
+	key := Key(big.NewInt(12345))
+	ciphertext, _ := EncryptMessage([]byte("hello"), key)
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+		key        []byte
+	}{
+		{"short", ciphertext[:16], key},
+		{"unaligned", append(ciphertext, 0), key},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptMessage(tt.ciphertext, tt.key); err == nil {
+				t.Errorf("DecryptMessage() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package hmac implements HMAC as defined in RFC 2104, over any hash in
+// this repository (or the standard library).
+// Cryptopals Set 4, Challenge 31
+// https://cryptopals.com/sets/4/challenges/31
+package hmac
+
+import (
+	"crypto/subtle"
+	"hash"
+
+	"github.com/adavidalbertson/cryptopals/hash/sha1"
+)
+
+type hmac struct {
+	inner, outer hash.Hash
+	ipad, opad   []byte
+}
+
+// New returns a hash.Hash computing HMAC with the given hash and key.
+// Keys longer than the block size are hashed first, as in RFC 2104.
+func New(h func() hash.Hash, key []byte) hash.Hash {
+	m := &hmac{inner: h(), outer: h()}
+	blockSize := m.inner.BlockSize()
+
+	if len(key) > blockSize {
+		m.outer.Write(key)
+		key = m.outer.Sum(nil)
+		m.outer.Reset()
+	}
+
+	m.ipad = make([]byte, blockSize)
+	m.opad = make([]byte, blockSize)
+	copy(m.ipad, key)
+	copy(m.opad, key)
+	for i := range m.ipad {
+		m.ipad[i] ^= 0x36
+		m.opad[i] ^= 0x5c
+	}
+
+	m.Reset()
+
+	return m
+}
+
+// NewSha1 returns a hash.Hash computing HMAC-SHA1 with the given key,
+// using package hash/sha1.
+func NewSha1(key []byte) hash.Hash {
+	return New(func() hash.Hash { return sha1.New() }, key)
+}
+
+// Sha1 returns the HMAC-SHA1 of message under key.
+func Sha1(key, message []byte) []byte {
+	m := NewSha1(key)
+	m.Write(message)
+
+	return m.Sum(nil)
+}
+
+// Equal compares two MACs in constant time.
+func Equal(mac1, mac2 []byte) bool {
+	return subtle.ConstantTimeCompare(mac1, mac2) == 1
+}
+
+func (m *hmac) Write(p []byte) (n int, err error) {
+	return m.inner.Write(p)
+}
+
+func (m *hmac) Sum(b []byte) []byte {
+	innerSum := m.inner.Sum(nil)
+
+	m.outer.Reset()
+	m.outer.Write(m.opad)
+	m.outer.Write(innerSum)
+
+	return m.outer.Sum(b)
+}
+
+func (m *hmac) Reset() {
+	m.inner.Reset()
+	m.inner.Write(m.ipad)
+}
+
+func (m *hmac) Size() int {
+	return m.outer.Size()
+}
+
+func (m *hmac) BlockSize() int {
+	return m.inner.BlockSize()
+}
@@ -0,0 +1,108 @@
+package hmac
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// InsecureCompare compares a and b a byte at a time, sleeping for delay
+// after each matching byte and returning at the first mismatch, so the time
+// taken leaks the length of the matching prefix.
+// Cryptopals Set 4, Challenge 31
+// https://cryptopals.com/sets/4/challenges/31
+func InsecureCompare(a, b []byte, delay time.Duration) bool {
+	for i := range a {
+		if i >= len(b) || a[i] != b[i] {
+			return false
+		}
+		time.Sleep(delay)
+	}
+
+	return len(a) == len(b)
+}
+
+// Server checks HMAC-SHA1 file signatures with InsecureCompare.
+// It answers GET ?file=foo&signature=<hex> with 200 if the signature is
+// valid, and 500 if not.
+// Cryptopals Set 4, Challenges 31 and 32
+// https://cryptopals.com/sets/4/challenges/31
+type Server struct {
+	key   []byte
+	delay time.Duration
+}
+
+var _ oracle.MacVerifier = Server{}
+
+// NewServer returns a Server with a random key which sleeps for delay after
+// each matching byte of a signature.
+func NewServer(delay time.Duration) Server {
+	return Server{random.Bytes(16), delay}
+}
+
+// Verify returns true if signature is the HMAC-SHA1 of file, leaking
+// timing just as requests over HTTP do.
+func (s Server) Verify(file, signature []byte) (valid bool, err error) {
+	return InsecureCompare(Sha1(s.key, file), signature, s.delay), nil
+}
+
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		http.Error(w, "invalid signature encoding", http.StatusBadRequest)
+		return
+	}
+
+	valid, _ := s.Verify([]byte(query.Get("file")), signature)
+	if !valid {
+		http.Error(w, "invalid signature", http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintln(w, "OK")
+}
+
+// Client checks file signatures against a Server at URL.
+// If HTTPClient is nil, http.DefaultClient is used.
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+var _ oracle.MacVerifier = Client{}
+
+// Verify asks the server whether signature is valid for file.
+func (c Client) Verify(file, signature []byte) (valid bool, err error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	query := url.Values{}
+	query.Set("file", string(file))
+	query.Set("signature", hex.EncodeToString(signature))
+
+	resp, err := client.Get(c.URL + "?" + query.Encode())
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusInternalServerError:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("Server returned %s", resp.Status)
+}
@@ -0,0 +1,129 @@
+package hmac
+
+import (
+	stdhmac "crypto/hmac"
+	stdsha1 "crypto/sha1"
+	"encoding/hex"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// RFC 2202, Section 3
+func TestSha1_rfc2202(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		message []byte
+		want    []byte
+	}{
+		{"case_1", decodeHex(strings.Repeat("0b", 20)), []byte("Hi There"), decodeHex("b617318655057264e28bc0b6fb378c8ef146be00")},
+		{"case_2", []byte("Jefe"), []byte("what do ya want for nothing?"), decodeHex("effcdf6ae5eb2fa2d27416d5f184df9c259a7c79")},
+		{"case_3", decodeHex(strings.Repeat("aa", 20)), decodeHex(strings.Repeat("dd", 50)), decodeHex("125d7342b9ac11cd91a39af48aa17b4f63f175d3")},
+		{"case_6_long_key", decodeHex(strings.Repeat("aa", 80)), []byte("Test Using Larger Than Block-Size Key - Hash Key First"), decodeHex("aa4ae5e15272d00e95705637ce8a3b55ed402112")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sha1(tt.key, tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sha1() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSha1_matchCryptoHmac(t *testing.T) {
+	for _, keyLength := range []int{0, 16, 64, 100} {
+		key, message := random.Bytes(keyLength), random.Bytes(200)
+
+		m := NewSha1(key)
+		m.Write(message[:50])
+		m.Write(message[50:])
+		got := m.Sum(nil)
+
+		want := stdhmac.New(stdsha1.New, key)
+		want.Write(message)
+		if !reflect.DeepEqual(got, want.Sum(nil)) {
+			t.Errorf("NewSha1() with %d byte key = %x, want %x", keyLength, got, want.Sum(nil))
+		}
+
+		// Reset must restore the keyed state
+		m.Reset()
+		m.Write(message)
+		if !reflect.DeepEqual(m.Sum(nil), want.Sum(nil)) {
+			t.Errorf("NewSha1() after Reset() = %x, want %x", m.Sum(nil), want.Sum(nil))
+		}
+	}
+}
+
+func TestInsecureCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []byte
+		want bool
+	}{
+		{"equal", []byte("abc"), []byte("abc"), true},
+		{"mismatch", []byte("abc"), []byte("abd"), false},
+		{"short", []byte("abc"), []byte("ab"), false},
+		{"long", []byte("abc"), []byte("abcd"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsecureCompare(tt.a, tt.b, 0); got != tt.want {
+				t.Errorf("InsecureCompare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	delay := 5 * time.Millisecond
+	start := time.Now()
+	InsecureCompare([]byte("abc"), []byte("abd"), delay)
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Errorf("InsecureCompare() took %v with 2 matching bytes, want at least %v", elapsed, 2*delay)
+	}
+}
+
+func TestClient_Verify(t *testing.T) {
+	s := NewServer(0)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	c := Client{server.URL + "/test", server.Client()}
+
+	file := []byte("foo")
+	signature := Sha1(s.key, file)
+
+	tests := []struct {
+		name      string
+		file      []byte
+		signature []byte
+		want      bool
+	}{
+		{"valid", file, signature, true},
+		{"wrong_file", []byte("bar"), signature, false},
+		{"wrong_signature", file, make([]byte, len(signature)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Verify(tt.file, tt.signature)
+			if err != nil {
+				t.Errorf("Client.Verify() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Client.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package md implements a toy Merkle–Damgård hash, small enough that
+// generic attacks on the construction run in seconds.
+// The compression function encrypts each message block with AES, keyed by
+// the chaining value, and truncates the result to the hash size.
+// Cryptopals Set 7, Challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+package md
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+)
+
+// BlockSize is the size of a message block in bytes: one AES block.
+const BlockSize = 16
+
+// default initial chaining value, truncated to the hash size
+var defaultIv = []byte{0x01, 0x23, 0x45, 0x67}
+
+// Hash is a Merkle–Damgård hash with a state of 16 to 32 bits.
+type Hash struct {
+	iv []byte
+}
+
+// New returns a Hash of the given number of bits (16, 24, or 32), starting
+// from a fixed initial state.
+func New(bits int) (h Hash, err error) {
+	if bits < 16 || bits > 32 || bits%8 != 0 {
+		return h, fmt.Errorf("Hash size must be 16, 24, or 32 bits, got %d", bits)
+	}
+
+	return NewWithIv(defaultIv[:bits/8])
+}
+
+// NewWithIv returns a Hash starting from iv, which sets the size of the hash.
+func NewWithIv(iv []byte) (h Hash, err error) {
+	if len(iv) < 2 || len(iv) > 4 {
+		return h, fmt.Errorf("Initial state must be 2 to 4 bytes, got %d", len(iv))
+	}
+
+	return Hash{append([]byte{}, iv...)}, nil
+}
+
+// Size returns the size of the hash, and of its state, in bytes.
+func (h Hash) Size() int {
+	return len(h.iv)
+}
+
+// Iv returns the initial state.
+func (h Hash) Iv() []byte {
+	return append([]byte{}, h.iv...)
+}
+
+// Compress returns the state after one block: block encrypted under state,
+// zero-padded to an AES key, and truncated to the size of state.
+func (h Hash) Compress(state, block []byte) []byte {
+	key := make([]byte, 16)
+	copy(key, state)
+
+	// the key is always 16 bytes, so the cipher can't fail
+	cipher, _ := ecb.NewCipher(key)
+	out := make([]byte, BlockSize)
+	cipher.Encrypt(out, block)
+
+	return out[:len(state)]
+}
+
+// Chain runs the compression function from state over blocks, which must be
+// a whole number of blocks, and returns the final state.
+func (h Hash) Chain(state, blocks []byte) []byte {
+	for i := 0; i+BlockSize <= len(blocks); i += BlockSize {
+		state = h.Compress(state, blocks[i:i+BlockSize])
+	}
+
+	return state
+}
+
+// Pad returns the padding for a message of length bytes: 0x80, zeros, and
+// the length in bits as a big-endian uint64, filling out the last block.
+func Pad(length uint64) []byte {
+	padLen := BlockSize - int((length+8)%BlockSize)
+	pad := make([]byte, padLen+8)
+	pad[0] = 0x80
+	binary.BigEndian.PutUint64(pad[padLen:], length*8)
+
+	return pad
+}
+
+// Sum returns the hash of message.
+func (h Hash) Sum(message []byte) []byte {
+	padded := append(append([]byte{}, message...), Pad(uint64(len(message)))...)
+
+	return h.Chain(h.iv, padded)
+}
+
+// Cascade concatenates the digests of two hashes, F and G. It is no harder
+// to collide than G alone: multicollisions in F are cheap to find, and
+// among enough of them G collides by the birthday bound.
+// Cryptopals Set 7, Challenge 52
+// https://cryptopals.com/sets/7/challenges/52
+type Cascade struct {
+	F, G Hash
+}
+
+// Sum returns F(message) || G(message).
+func (c Cascade) Sum(message []byte) []byte {
+	return append(c.F.Sum(message), c.G.Sum(message)...)
+}
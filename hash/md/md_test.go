@@ -0,0 +1,80 @@
+package md
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		bits     int
+		wantSize int
+		wantErr  bool
+	}{
+		{"16_bits", 16, 2, false},
+		{"24_bits", 24, 3, false},
+		{"32_bits", 32, 4, false},
+		{"too_small", 8, 0, true},
+		{"too_big", 64, 0, true},
+		{"not_whole_bytes", 20, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := New(tt.bits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if h.Size() != tt.wantSize {
+				t.Errorf("New().Size() = %d, want %d", h.Size(), tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	for _, length := range []uint64{0, 7, 8, 9, 16, 100} {
+		pad := Pad(length)
+		if (length+uint64(len(pad)))%BlockSize != 0 || len(pad) < 9 || len(pad) > BlockSize+8 {
+			t.Errorf("Pad(%d) has length %d", length, len(pad))
+		}
+	}
+}
+
+func TestHash_Sum(t *testing.T) {
+	h, _ := New(32)
+	message := random.Bytes(3*BlockSize + 5)
+
+	padded := append(append([]byte{}, message...), Pad(uint64(len(message)))...)
+	state := h.Iv()
+	for i := 0; i < len(padded); i += BlockSize {
+		state = h.Compress(state, padded[i:i+BlockSize])
+	}
+
+	got := h.Sum(message)
+	if !reflect.DeepEqual(got, state) {
+		t.Errorf("Hash.Sum() = %x, want %x", got, state)
+	}
+	if len(got) != h.Size() {
+		t.Errorf("Hash.Sum() has length %d, want %d", len(got), h.Size())
+	}
+
+	// the length is part of the padding
+	if reflect.DeepEqual(h.Sum(message), h.Sum(message[:len(message)-1])) {
+		t.Errorf("Hash.Sum() is the same for different messages")
+	}
+}
+
+func TestCascade_Sum(t *testing.T) {
+	f, _ := New(16)
+	g, _ := New(24)
+	message := []byte("YELLOW SUBMARINE")
+
+	want := append(f.Sum(message), g.Sum(message)...)
+	if got := (Cascade{f, g}).Sum(message); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cascade.Sum() = %x, want %x", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+// Package md4 implements MD4 as defined in RFC 1320, with its internal
+// state exposed so it can be saved, restored, or forged, in the same way as
+// package hash/sha1. The block buffering and padding come from hash/mdhash.
+// Cryptopals Set 4, Challenge 30
+// https://cryptopals.com/sets/4/challenges/30
+package md4
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+
+	"github.com/adavidalbertson/cryptopals/hash/mdhash"
+)
+
+// Size is the size of an MD4 digest in bytes.
+const Size = 16
+
+// BlockSize is the block size of MD4 in bytes.
+const BlockSize = mdhash.BlockSize
+
+// Hash is MD4: little-endian, with four chaining registers.
+var Hash = mdhash.Hash{
+	Name:  "MD4",
+	Order: binary.LittleEndian,
+	Init:  []uint32{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476},
+	Block: Block,
+}
+
+// State is the internal state of MD4 between blocks.
+type State = mdhash.State
+
+// Digest computes an MD4 hash. It implements hash.Hash.
+type Digest = mdhash.Digest
+
+// New returns a Digest in the standard initial state.
+func New() *Digest {
+	return Hash.New()
+}
+
+// newHash is New as a hash.Hash constructor.
+func newHash() hash.Hash {
+	return New()
+}
+
+// NewFromState returns a Digest which continues from the given state.
+func NewFromState(state State) *Digest {
+	return Hash.NewFromState(state)
+}
+
+// StateFromSum recovers the state after a message of length bytes
+// (including padding) from its digest.
+func StateFromSum(sum []byte, length uint64) (State, error) {
+	return Hash.StateFromSum(sum, length)
+}
+
+// Sum returns the MD4 digest of data.
+func Sum(data []byte) (sum [Size]byte) {
+	copy(sum[:], Hash.Sum(data))
+
+	return
+}
+
+// HashString returns the MD4 digest of s, for fileutils.ByteSlicesFromFile.
+func HashString(s string) ([]byte, error) {
+	return Hash.Sum([]byte(s)), nil
+}
+
+// Pad returns the padding MD4 appends to a message of length bytes,
+// ending with the length in bits as a little-endian uint64.
+func Pad(length uint64) []byte {
+	return Hash.Pad(length)
+}
+
+// message word order for each round
+var (
+	round2Order = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	round3Order = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+// per-step rotation amounts for each round
+var (
+	round1Shifts = [4]int{3, 7, 11, 19}
+	round2Shifts = [4]int{3, 5, 9, 13}
+	round3Shifts = [4]int{3, 9, 11, 15}
+)
+
+// Block runs the MD4 compression function on one 64-byte block,
+// updating the chaining registers h.
+func Block(h []uint32, block []byte) {
+	if len(block) != BlockSize {
+		panic("md4: block must be 64 bytes")
+	}
+
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+
+	a, b, c, d := h[0], h[1], h[2], h[3]
+
+	for i := 0; i < 16; i++ {
+		f := (b & c) | (^b & d)
+		a = bits.RotateLeft32(a+f+x[i], round1Shifts[i%4])
+		a, b, c, d = d, a, b, c
+	}
+
+	for i := 0; i < 16; i++ {
+		g := (b & c) | (b & d) | (c & d)
+		a = bits.RotateLeft32(a+g+x[round2Order[i]]+0x5A827999, round2Shifts[i%4])
+		a, b, c, d = d, a, b, c
+	}
+
+	for i := 0; i < 16; i++ {
+		hh := b ^ c ^ d
+		a = bits.RotateLeft32(a+hh+x[round3Order[i]]+0x6ED9EBA1, round3Shifts[i%4])
+		a, b, c, d = d, a, b, c
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+}
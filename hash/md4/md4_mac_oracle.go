@@ -0,0 +1,18 @@
+package md4
+
+import "github.com/adavidalbertson/cryptopals/hash/mdhash"
+
+// SecretPrefixMac returns MD4(key || message).
+// Cryptopals Set 4, Challenge 30
+// https://cryptopals.com/sets/4/challenges/30
+func SecretPrefixMac(key, message []byte) []byte {
+	return mdhash.SecretPrefixMac(newHash, key, message)
+}
+
+// NewMacOracle returns a secret-prefix MD4 MAC oracle with a random key of
+// 8-32 bytes.
+// Cryptopals Set 4, Challenge 30
+// https://cryptopals.com/sets/4/challenges/30
+func NewMacOracle() mdhash.MacOracle {
+	return mdhash.NewMacOracle(newHash)
+}
@@ -0,0 +1,134 @@
+package md4
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/fileutils"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// RFC 1320, Appendix A.5, one message per line of testVectors.txt
+func TestHashString_rfc1320(t *testing.T) {
+	want := [][]byte{
+		decodeHex("31d6cfe0d16ae931b73c59d7e0c089c0"),
+		decodeHex("bde52cb31de33e46245e05fbdbd6fb24"),
+		decodeHex("a448017aaf21d8525fc10ae87aa6729d"),
+		decodeHex("d9130a8164549fe818874806e1c7014b"),
+		decodeHex("d79e1c308aa5bbcdeea8ed63df412da9"),
+		decodeHex("043f8582f241db351ce627e153e7f0e4"),
+		decodeHex("e33b4ddc9c38f2199c3e7b164fcc0536"),
+	}
+
+	got, err := fileutils.ByteSlicesFromFile("./testVectors.txt", HashString)
+	if err != nil {
+		t.Errorf("ByteSlicesFromFile() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ByteSlicesFromFile(HashString) = %x, want %x", got, want)
+	}
+}
+
+func TestDigest_Write(t *testing.T) {
+	message := random.Bytes(5 * BlockSize / 2)
+	want := Sum(message)
+
+	for _, chunk := range []int{1, 7, BlockSize, 100} {
+		d := New()
+		for i := 0; i < len(message); i += chunk {
+			end := i + chunk
+			if end > len(message) {
+				end = len(message)
+			}
+			d.Write(message[i:end])
+		}
+
+		if got := d.Sum(nil); !reflect.DeepEqual(got, want[:]) {
+			t.Errorf("Digest.Sum() in chunks of %d = %x, want %x", chunk, got, want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		name   string
+		length uint64
+		want   []byte
+	}{
+		{"abc", 3, append(append([]byte{0x80}, make([]byte, 52)...), 24, 0, 0, 0, 0, 0, 0, 0)},
+		{"fits_exactly", 55, []byte{0x80, 0xb8, 1, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pad(tt.length); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pad() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetState(t *testing.T) {
+	prefix := random.Bytes(2 * BlockSize)
+	suffix := []byte(";admin=true")
+
+	d := New()
+	d.Write(prefix)
+	state := d.State()
+	d.Write(suffix)
+	want := d.Sum(nil)
+
+	resumed := NewFromState(state)
+	resumed.Write(suffix)
+	if got := resumed.Sum(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFromState().Sum() = %x, want %x", got, want)
+	}
+
+	sum := Sum(prefix)
+	glue := Pad(uint64(len(prefix)))
+	state, err := StateFromSum(sum[:], uint64(len(prefix)+len(glue)))
+	if err != nil {
+		t.Errorf("StateFromSum() error = %v", err)
+		return
+	}
+	extended := NewFromState(state)
+	extended.Write(suffix)
+	glued := append(append(append([]byte{}, prefix...), glue...), suffix...)
+	if got, want := extended.Sum(nil), Sum(glued); !reflect.DeepEqual(got, want[:]) {
+		t.Errorf("StateFromSum() extension = %x, want %x", got, want)
+	}
+}
+
+func TestMacOracle(t *testing.T) {
+	mac := NewMacOracle()
+	message := []byte("comment1=cooking%20MCs;userdata=foo")
+	tag, _ := mac.Sign(message)
+
+	tests := []struct {
+		name    string
+		message []byte
+		tag     []byte
+		want    bool
+	}{
+		{"valid", message, tag, true},
+		{"tampered_message", []byte("comment1=cooking%20MCs;userdata=bar"), tag, false},
+		{"keyless_tag", message, SecretPrefixMac(nil, message), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := mac.Verify(tt.message, tt.tag); got != tt.want {
+				t.Errorf("MacOracle.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package mdhash
+
+import (
+	"crypto/subtle"
+	"hash"
+	mrand "math/rand"
+	"time"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// SecretPrefixMac returns H(key || message), for the hash made by newHash.
+// Cryptopals Set 4, Challenge 28
+// https://cryptopals.com/sets/4/challenges/28
+func SecretPrefixMac(newHash func() hash.Hash, key, message []byte) []byte {
+	h := newHash()
+	h.Write(key)
+	h.Write(message)
+
+	return h.Sum(nil)
+}
+
+// MacOracle signs and verifies messages with a secret-prefix MAC under a
+// key of unknown length.
+// Cryptopals Set 4, Challenges 29 and 30
+// https://cryptopals.com/sets/4/challenges/29
+// https://cryptopals.com/sets/4/challenges/30
+type MacOracle struct {
+	newHash func() hash.Hash
+	key     []byte
+}
+
+var _ oracle.MacSigner = MacOracle{}
+var _ oracle.MacVerifier = MacOracle{}
+
+// NewMacOracle returns a MacOracle for the hash made by newHash, with a
+// random key of 8-32 bytes.
+func NewMacOracle(newHash func() hash.Hash) MacOracle {
+	r := mrand.New(mrand.NewSource(time.Now().UnixNano()))
+
+	return MacOracle{newHash, random.Bytes(8 + r.Intn(25))}
+}
+
+// Sign returns the MAC of message under the oracle's key.
+func (oracle MacOracle) Sign(message []byte) (tag []byte, err error) {
+	return SecretPrefixMac(oracle.newHash, oracle.key, message), nil
+}
+
+// Verify returns true if tag is the MAC of message under the oracle's key.
+func (oracle MacOracle) Verify(message, tag []byte) (valid bool, err error) {
+	return subtle.ConstantTimeCompare(SecretPrefixMac(oracle.newHash, oracle.key, message), tag) == 1, nil
+}
@@ -0,0 +1,176 @@
+// Package mdhash implements the Merkle–Damgård framing shared by hash/sha1
+// and hash/md4: buffering input into 64-byte blocks, appending the length
+// padding, and converting between digests and chaining registers.
+// A Hash supplies only what differs between the two, its byte order,
+// initial registers and compression function. The internal state stays
+// exposed, so it can be saved, restored, or forged.
+package mdhash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// BlockSize is the block size in bytes of every Hash.
+const BlockSize = 64
+
+// State is the internal state of a Hash between blocks: the chaining
+// registers, and the number of message bytes processed so far.
+type State struct {
+	H      []uint32
+	Length uint64
+}
+
+// Hash describes a Merkle–Damgård hash over 32-bit chaining registers and
+// 64-byte blocks, padded with a 1 bit, zeros, and the message length in bits.
+type Hash struct {
+	Name string
+	// Order is the byte order of the message words, the length in the
+	// padding, and the registers in the digest.
+	Order binary.ByteOrder
+	// Init holds the initial values of the chaining registers.
+	Init []uint32
+	// Block runs the compression function on one block, updating h.
+	Block func(h []uint32, block []byte)
+}
+
+// Size returns the size of a digest in bytes.
+func (f Hash) Size() int {
+	return 4 * len(f.Init)
+}
+
+// New returns a Digest in the standard initial state.
+func (f Hash) New() *Digest {
+	d := &Digest{f: f}
+	d.Reset()
+
+	return d
+}
+
+// NewFromState returns a Digest which continues from the given state, as if
+// it had already processed state.Length bytes ending on a block boundary.
+func (f Hash) NewFromState(state State) *Digest {
+	d := &Digest{f: f}
+	d.SetState(state)
+
+	return d
+}
+
+// StateFromSum recovers the chaining registers from a digest, and pairs them
+// with the length of the message (including padding) the digest covers.
+func (f Hash) StateFromSum(sum []byte, length uint64) (state State, err error) {
+	if len(sum) != f.Size() {
+		return state, fmt.Errorf("%s digest must be %d bytes, got %d", f.Name, f.Size(), len(sum))
+	}
+
+	state.H = make([]uint32, len(f.Init))
+	for i := range state.H {
+		state.H[i] = f.Order.Uint32(sum[4*i:])
+	}
+	state.Length = length
+
+	return
+}
+
+// Pad returns the padding appended to a message of length bytes: a 1 bit,
+// zeros up to 8 bytes short of a block boundary, then the message length
+// in bits as a uint64.
+func (f Hash) Pad(length uint64) []byte {
+	padLen := BlockSize - int((length+8)%BlockSize)
+	pad := make([]byte, padLen+8)
+	pad[0] = 0x80
+	f.Order.PutUint64(pad[padLen:], length*8)
+
+	return pad
+}
+
+// Sum returns the digest of data.
+func (f Hash) Sum(data []byte) []byte {
+	d := f.New()
+	d.Write(data)
+
+	return d.Sum(nil)
+}
+
+// Digest computes a Hash. It implements hash.Hash.
+type Digest struct {
+	f   Hash
+	h   []uint32
+	x   [BlockSize]byte
+	nx  int
+	len uint64
+}
+
+var _ hash.Hash = &Digest{}
+
+// Reset restores the standard initial state.
+func (d *Digest) Reset() {
+	d.SetState(State{d.f.Init, 0})
+}
+
+// State returns the chaining registers and the number of bytes written.
+// It is a complete description of d only when the bytes written so far are
+// a multiple of BlockSize; otherwise the buffered partial block is left out.
+func (d *Digest) State() State {
+	return State{append([]uint32(nil), d.h...), d.len}
+}
+
+// SetState overwrites the chaining registers and length counter, and
+// discards any buffered partial block.
+func (d *Digest) SetState(state State) {
+	d.h = append([]uint32(nil), state.H...)
+	d.len = state.Length
+	d.nx = 0
+}
+
+// Size returns the size of a digest in bytes.
+func (d *Digest) Size() int {
+	return d.f.Size()
+}
+
+// BlockSize returns the block size in bytes.
+func (d *Digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write adds p to the running hash. It never returns an error.
+func (d *Digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.len += uint64(n)
+
+	if d.nx > 0 {
+		copied := copy(d.x[d.nx:], p)
+		d.nx += copied
+		p = p[copied:]
+		if d.nx < BlockSize {
+			return
+		}
+		d.f.Block(d.h, d.x[:])
+		d.nx = 0
+	}
+
+	for len(p) >= BlockSize {
+		d.f.Block(d.h, p[:BlockSize])
+		p = p[BlockSize:]
+	}
+
+	d.nx = copy(d.x[:], p)
+
+	return
+}
+
+// Sum appends the digest of the bytes written so far to b.
+// It does not change the state of d.
+func (d *Digest) Sum(b []byte) []byte {
+	clone := *d
+	clone.h = append([]uint32(nil), d.h...)
+	clone.Write(d.f.Pad(d.len))
+
+	sum := make([]byte, d.Size())
+	for i, h := range clone.h {
+		d.f.Order.PutUint32(sum[4*i:], h)
+	}
+
+	return append(b, sum...)
+}
@@ -0,0 +1,112 @@
+package mdhash
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// sum32 is a toy Hash whose compression function adds up the block's words
+// into each register, enough to check the framing.
+var sum32 = Hash{
+	Name:  "sum32",
+	Order: binary.BigEndian,
+	Init:  []uint32{1, 2},
+	Block: func(h []uint32, block []byte) {
+		for i := range h {
+			for j := 0; j < BlockSize; j += 4 {
+				h[i] += binary.BigEndian.Uint32(block[j:]) * uint32(i+1)
+			}
+		}
+	},
+}
+
+func TestDigest_Sum(t *testing.T) {
+	message := random.Bytes(5 * BlockSize / 2)
+	want := sum32.Sum(message)
+
+	for _, chunk := range []int{1, 7, BlockSize, 100} {
+		d := sum32.New()
+		for i := 0; i < len(message); i += chunk {
+			end := i + chunk
+			if end > len(message) {
+				end = len(message)
+			}
+			d.Write(message[i:end])
+		}
+
+		// Sum must not disturb the running hash
+		for i := 0; i < 2; i++ {
+			if got := d.Sum(nil); !reflect.DeepEqual(got, want) {
+				t.Errorf("Digest.Sum() in chunks of %d = %x, want %x", chunk, got, want)
+			}
+		}
+	}
+}
+
+func TestDigest_State(t *testing.T) {
+	d := sum32.New()
+	d.Write(random.Bytes(BlockSize))
+	state := d.State()
+	want := d.Sum(nil)
+
+	// the state is a copy, so neither side sees the other's changes
+	state.H[0]++
+	if got := d.Sum(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Digest.Sum() after changing State().H = %x, want %x", got, want)
+	}
+	d.Write(random.Bytes(BlockSize))
+	if got := sum32.NewFromState(state).State(); !reflect.DeepEqual(got, state) {
+		t.Errorf("NewFromState().State() = %v, want %v", got, state)
+	}
+}
+
+func TestHash_StateFromSum(t *testing.T) {
+	message := random.Bytes(BlockSize)
+	padded := uint64(len(message) + len(sum32.Pad(uint64(len(message)))))
+
+	state, err := sum32.StateFromSum(sum32.Sum(message), padded)
+	if err != nil {
+		t.Errorf("StateFromSum() error = %v", err)
+		return
+	}
+	d := sum32.New()
+	d.Write(message)
+	d.Write(sum32.Pad(uint64(len(message))))
+	if want := d.State(); !reflect.DeepEqual(state, want) {
+		t.Errorf("StateFromSum() = %v, want %v", state, want)
+	}
+
+	if _, err := sum32.StateFromSum(make([]byte, 3), 0); err == nil {
+		t.Errorf("StateFromSum() of short digest error = <nil>, want error")
+	}
+}
+
+func TestMacOracle(t *testing.T) {
+	// any hash.Hash will do, not only the ones in this package
+	mac := NewMacOracle(func() hash.Hash { return sha256.New() })
+	message := []byte("comment1=cooking%20MCs;userdata=foo")
+
+	tag, _ := mac.Sign(message)
+	tests := []struct {
+		name    string
+		message []byte
+		tag     []byte
+		want    bool
+	}{
+		{"own_tag", message, tag, true},
+		{"tampered", []byte("comment1=cooking%20MCs;userdata=bar"), tag, false},
+		{"keyless_tag", message, SecretPrefixMac(func() hash.Hash { return sha256.New() }, nil, message), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := mac.Verify(tt.message, tt.tag); got != tt.want {
+				t.Errorf("MacOracle.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Package sha1 implements SHA-1 as defined in FIPS 180-4, with its internal
+// state exposed so it can be saved, restored, or forged.
+// Unlike crypto/sha1, the chaining registers and the length counter can be
+// set directly, which is what a length extension attack needs.
+// The block buffering and padding are shared with hash/md4 through
+// hash/mdhash; this package supplies the compression function.
+// Cryptopals Set 4, Challenge 28
+// https://cryptopals.com/sets/4/challenges/28
+package sha1
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+
+	"github.com/adavidalbertson/cryptopals/hash/mdhash"
+)
+
+// Size is the size of a SHA-1 digest in bytes.
+const Size = 20
+
+// BlockSize is the block size of SHA-1 in bytes.
+const BlockSize = mdhash.BlockSize
+
+// Hash is SHA-1: big-endian, with five chaining registers.
+var Hash = mdhash.Hash{
+	Name:  "SHA-1",
+	Order: binary.BigEndian,
+	Init:  []uint32{0x67452301, 0xEFCDAB89, 0x98BADCFE, 0x10325476, 0xC3D2E1F0},
+	Block: Block,
+}
+
+// State is the internal state of SHA-1 between blocks.
+type State = mdhash.State
+
+// Digest computes a SHA-1 hash. It implements hash.Hash.
+type Digest = mdhash.Digest
+
+// New returns a Digest in the standard initial state.
+func New() *Digest {
+	return Hash.New()
+}
+
+// newHash is New as a hash.Hash constructor.
+func newHash() hash.Hash {
+	return New()
+}
+
+// NewFromState returns a Digest which continues from the given state.
+func NewFromState(state State) *Digest {
+	return Hash.NewFromState(state)
+}
+
+// StateFromSum recovers the state after a message of length bytes
+// (including padding) from its digest.
+func StateFromSum(sum []byte, length uint64) (State, error) {
+	return Hash.StateFromSum(sum, length)
+}
+
+// Sum returns the SHA-1 digest of data.
+func Sum(data []byte) (sum [Size]byte) {
+	copy(sum[:], Hash.Sum(data))
+
+	return
+}
+
+// HashString returns the SHA-1 digest of s. It has the same signature as
+// the decode functions taken by fileutils, so each line of a file can be
+// hashed with fileutils.ByteSlicesFromFile.
+func HashString(s string) ([]byte, error) {
+	return Hash.Sum([]byte(s)), nil
+}
+
+// Pad returns the padding SHA-1 appends to a message of length bytes,
+// ending with the length in bits as a big-endian uint64.
+func Pad(length uint64) []byte {
+	return Hash.Pad(length)
+}
+
+// Block runs the SHA-1 compression function on one 64-byte block,
+// updating the chaining registers h.
+func Block(h []uint32, block []byte) {
+	if len(block) != BlockSize {
+		panic("sha1: block must be 64 bytes")
+	}
+
+	var w [80]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+
+	a, b, c, d, e := h[0], h[1], h[2], h[3], h[4]
+	for i := 0; i < 80; i++ {
+		var f, k uint32
+		switch {
+		case i < 20:
+			f, k = (b&c)|(^b&d), 0x5A827999
+		case i < 40:
+			f, k = b^c^d, 0x6ED9EBA1
+		case i < 60:
+			f, k = (b&c)|(b&d)|(c&d), 0x8F1BBCDC
+		default:
+			f, k = b^c^d, 0xCA62C1D6
+		}
+
+		temp := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+		a, b, c, d, e = temp, a, bits.RotateLeft32(b, 30), c, d
+	}
+
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
+}
@@ -0,0 +1,18 @@
+package sha1
+
+import "github.com/adavidalbertson/cryptopals/hash/mdhash"
+
+// SecretPrefixMac returns SHA1(key || message).
+// Cryptopals Set 4, Challenge 28
+// https://cryptopals.com/sets/4/challenges/28
+func SecretPrefixMac(key, message []byte) []byte {
+	return mdhash.SecretPrefixMac(newHash, key, message)
+}
+
+// NewMacOracle returns a secret-prefix SHA-1 MAC oracle with a random key
+// of 8-32 bytes.
+// Cryptopals Set 4, Challenge 29
+// https://cryptopals.com/sets/4/challenges/29
+func NewMacOracle() mdhash.MacOracle {
+	return mdhash.NewMacOracle(newHash)
+}
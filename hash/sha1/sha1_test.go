@@ -0,0 +1,171 @@
+package sha1
+
+import (
+	stdsha1 "crypto/sha1"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/fileutils"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// FIPS 180 examples, one message per line of testVectors.txt
+func TestHashString_fips180(t *testing.T) {
+	want := [][]byte{
+		decodeHex("da39a3ee5e6b4b0d3255bfef95601890afd80709"),
+		decodeHex("a9993e364706816aba3e25717850c26c9cd0d89d"),
+		decodeHex("84983e441c3bd26ebaae4aa1f95129e5e54670f1"),
+		decodeHex("a49b2446a02c645bf419f995b67091253a04a259"),
+	}
+
+	got, err := fileutils.ByteSlicesFromFile("./testVectors.txt", HashString)
+	if err != nil {
+		t.Errorf("ByteSlicesFromFile() error = %v", err)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ByteSlicesFromFile(HashString) = %x, want %x", got, want)
+	}
+}
+
+func TestDigest_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		chunk   int
+		want    []byte
+	}{
+		{"million_a", strings.Repeat("a", 1000000), 1000, decodeHex("34aa973cd4c4daa4f61eeb2bdbad27316534016f")},
+		{"byte_at_a_time", "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", 1, decodeHex("84983e441c3bd26ebaae4aa1f95129e5e54670f1")},
+		{"uneven_chunks", "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", 7, decodeHex("84983e441c3bd26ebaae4aa1f95129e5e54670f1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			for i := 0; i < len(tt.message); i += tt.chunk {
+				end := i + tt.chunk
+				if end > len(tt.message) {
+					end = len(tt.message)
+				}
+				d.Write([]byte(tt.message[i:end]))
+			}
+
+			if got := d.Sum(nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Digest.Sum() = %x, want %x", got, tt.want)
+			}
+			// Sum must not disturb the running hash
+			if got := d.Sum(nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("second Digest.Sum() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSum_matchCryptoSha1(t *testing.T) {
+	for length := 0; length < 3*BlockSize; length++ {
+		message := random.Bytes(length)
+		if got, want := Sum(message), stdsha1.Sum(message); got != want {
+			t.Errorf("Sum() of %d bytes = %x, want %x", length, got, want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		name    string
+		length  uint64
+		wantLen int
+	}{
+		{"empty", 0, 64},
+		{"abc", 3, 61},
+		{"one_byte_short_of_fitting", 56, 72},
+		{"fits_exactly", 55, 9},
+		{"full_block", 64, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pad := Pad(tt.length)
+			if len(pad) != tt.wantLen {
+				t.Errorf("len(Pad()) = %v, want %v", len(pad), tt.wantLen)
+			}
+			if (tt.length+uint64(len(pad)))%BlockSize != 0 {
+				t.Errorf("Pad() does not end on a block boundary")
+			}
+			if pad[0] != 0x80 {
+				t.Errorf("Pad()[0] = %x, want 80", pad[0])
+			}
+		})
+	}
+}
+
+func TestSetState(t *testing.T) {
+	prefix := random.Bytes(2 * BlockSize)
+	suffix := []byte(";admin=true")
+
+	d := New()
+	d.Write(prefix)
+	state := d.State()
+	d.Write(suffix)
+	want := d.Sum(nil)
+
+	resumed := NewFromState(state)
+	resumed.Write(suffix)
+	if got := resumed.Sum(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFromState().Sum() = %x, want %x", got, want)
+	}
+
+	// a digest is the state after the padded message, so hashing can continue from it
+	sum := Sum(prefix)
+	glued := append(append(prefix, Pad(uint64(len(prefix)))...), suffix...)
+	state, err := StateFromSum(sum[:], uint64(len(prefix)+len(Pad(uint64(len(prefix))))))
+	if err != nil {
+		t.Errorf("StateFromSum() error = %v", err)
+		return
+	}
+	extended := NewFromState(state)
+	extended.Write(suffix)
+	if got, want := extended.Sum(nil), Sum(glued); !reflect.DeepEqual(got, want[:]) {
+		t.Errorf("StateFromSum() extension = %x, want %x", got, want)
+	}
+
+	if _, err := StateFromSum(sum[:10], 0); err == nil {
+		t.Errorf("StateFromSum() of short digest error = <nil>, want error")
+	}
+}
+
+func TestMacOracle(t *testing.T) {
+	mac := NewMacOracle()
+	message := []byte("comment1=cooking%20MCs;userdata=foo")
+	tag, _ := mac.Sign(message)
+
+	tests := []struct {
+		name    string
+		message []byte
+		tag     []byte
+		want    bool
+	}{
+		{"valid", message, tag, true},
+		{"tampered_message", []byte("comment1=cooking%20MCs;userdata=bar"), tag, false},
+		{"tampered_tag", message, append([]byte{tag[0] ^ 1}, tag[1:]...), false},
+		{"keyless_tag", message, SecretPrefixMac(nil, message), false},
+		{"short_tag", message, tag[:10], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := mac.Verify(tt.message, tt.tag); got != tt.want {
+				t.Errorf("MacOracle.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package modes
+
+import (
+	"crypto/cipher"
+)
+
+// cfb is a cipher.Stream for CFB mode with an s-byte segment size.
+// The shift register starts as the iv; after each segment, it is shifted
+// left by s bytes and the segment's ciphertext is fed in on the right.
+// NIST SP 800-38A, 6.3
+type cfb struct {
+	b           Block
+	register    []byte
+	out         []byte // keystream for the current segment
+	segment     []byte // ciphertext fed back so far for the current segment
+	segmentSize int
+	decrypt     bool
+}
+
+// NewCFBEncrypter returns a CFB encrypter with the given segment size in bytes.
+// Use b.BlockSize() for full-block CFB, or 1 for CFB-8.
+func NewCFBEncrypter(b Block, iv []byte, segmentSize int) cipher.Stream {
+	return newCFB(b, iv, segmentSize, false)
+}
+
+// NewCFBDecrypter returns a CFB decrypter with the given segment size in bytes.
+// Use b.BlockSize() for full-block CFB, or 1 for CFB-8.
+func NewCFBDecrypter(b Block, iv []byte, segmentSize int) cipher.Stream {
+	return newCFB(b, iv, segmentSize, true)
+}
+
+func newCFB(b Block, iv []byte, segmentSize int, decrypt bool) *cfb {
+	register := checkIV(b, iv, "cfb")
+	if segmentSize < 1 || segmentSize > b.BlockSize() {
+		panic("cfb: segment size must be between 1 and the block size")
+	}
+
+	return &cfb{b: b, register: register, segmentSize: segmentSize, decrypt: decrypt}
+}
+
+// XORKeyStream encrypts or decrypts src into dst.
+func (x *cfb) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("cfb: output smaller than input")
+	}
+
+	for i := range src {
+		if len(x.out) == 0 {
+			full := make([]byte, x.b.BlockSize())
+			x.b.Encrypt(full, x.register)
+			x.out = full[:x.segmentSize]
+		}
+
+		in := src[i]
+		dst[i] = in ^ x.out[0]
+		x.out = x.out[1:]
+
+		// the ciphertext byte is fed back, whichever direction we're going
+		if x.decrypt {
+			x.segment = append(x.segment, in)
+		} else {
+			x.segment = append(x.segment, dst[i])
+		}
+
+		if len(x.segment) == x.segmentSize {
+			x.register = append(x.register[x.segmentSize:], x.segment...)
+			x.segment = x.segment[:0]
+		}
+	}
+}
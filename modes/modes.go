@@ -0,0 +1,47 @@
+package modes
+
+import (
+	"crypto/cipher"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+)
+
+// Block is all a mode of operation needs from a block cipher.
+// It has the same method set as crypto/cipher.Block, so AES from ecb.NewCipher,
+// crypto/aes, rijndael.Cipher, and ToyCipher all satisfy it.
+type Block interface {
+	BlockSize() int
+	Encrypt(dst, src []byte)
+	Decrypt(dst, src []byte)
+}
+
+// NewCBCEncrypter returns a CBC encrypter over any block cipher.
+// This is the same implementation cbc.Encrypt uses.
+func NewCBCEncrypter(b Block, iv []byte) cipher.BlockMode {
+	return cbc.NewCBCEncrypter(b, iv)
+}
+
+// NewCBCDecrypter returns a CBC decrypter over any block cipher.
+// This is the same implementation cbc.Decrypt uses.
+func NewCBCDecrypter(b Block, iv []byte) cipher.BlockMode {
+	return cbc.NewCBCDecrypter(b, iv)
+}
+
+// NewCTR returns a CTR stream over any block cipher, with the given counter format.
+// This is the same implementation ctr.AesCtrCipher uses.
+func NewCTR(b Block, nonce []byte, format ctr.CounterFormat) cipher.Stream {
+	return ctr.NewCTRWithFormat(b, nonce, format)
+}
+
+// checkIV panics if the iv doesn't match the block size, like crypto/cipher does.
+func checkIV(b Block, iv []byte, mode string) []byte {
+	if len(iv) != b.BlockSize() {
+		panic(mode + ": IV length must equal block size")
+	}
+
+	ivCopy := make([]byte, len(iv))
+	copy(ivCopy, iv)
+
+	return ivCopy
+}
@@ -0,0 +1,268 @@
+package modes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// NIST SP 800-38A, Appendix F
+const (
+	sp80038aKey       = "2b7e151628aed2a6abf7158809cf4f3c"
+	sp80038aIV        = "000102030405060708090a0b0c0d0e0f"
+	sp80038aPlaintext = "6bc1bee22e409f96e93d7e117393172a" +
+		"ae2d8a571e03ac9c9eb76fac45af8e51" +
+		"30c81c46a35ce411e5fbc1191a0a52ef" +
+		"f69f2445df4f9b17ad2b417be66c3710"
+)
+
+func TestStreams_sp80038a(t *testing.T) {
+	block, _ := ecb.NewCipher(decodeHex(sp80038aKey))
+	iv := decodeHex(sp80038aIV)
+
+	tests := []struct {
+		name           string
+		encrypter      cipher.Stream
+		decrypter      cipher.Stream
+		plaintext      []byte
+		wantCiphertext []byte
+	}{
+		{
+			"f_3_7_cfb8_aes128",
+			NewCFBEncrypter(block, iv, 1),
+			NewCFBDecrypter(block, iv, 1),
+			decodeHex(sp80038aPlaintext[:36]),
+			decodeHex("3b79424c9c0dd436bace9e0ed4586a4f32b9"),
+		},
+		{
+			"f_3_13_cfb128_aes128",
+			NewCFBEncrypter(block, iv, 16),
+			NewCFBDecrypter(block, iv, 16),
+			decodeHex(sp80038aPlaintext),
+			decodeHex("3b3fd92eb72dad20333449f8e83cfb4a" +
+				"c8a64537a0b3a93fcde3cdad9f1ce58b" +
+				"26751f67a3cbb140b1808cf187a4f4df" +
+				"c04b05357c5d1c0eeac4c66f9ff7f2e6"),
+		},
+		{
+			"f_4_1_ofb_aes128",
+			NewOFB(block, iv),
+			NewOFB(block, iv),
+			decodeHex(sp80038aPlaintext),
+			decodeHex("3b3fd92eb72dad20333449f8e83cfb4a" +
+				"7789508d16918f03f53c52dac54ed825" +
+				"9740051e9c5fecf64344f7a82260edcc" +
+				"304c6528f659c77866a510d9c1d6ae5e"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCiphertext := make([]byte, len(tt.plaintext))
+			tt.encrypter.XORKeyStream(gotCiphertext, tt.plaintext)
+			if !reflect.DeepEqual(gotCiphertext, tt.wantCiphertext) {
+				t.Errorf("XORKeyStream() = %x, want %x", gotCiphertext, tt.wantCiphertext)
+			}
+
+			// decrypt a byte at a time to exercise partial segments
+			gotPlaintext := make([]byte, len(gotCiphertext))
+			for i := range gotCiphertext {
+				tt.decrypter.XORKeyStream(gotPlaintext[i:i+1], gotCiphertext[i:i+1])
+			}
+			if !reflect.DeepEqual(gotPlaintext, tt.plaintext) {
+				t.Errorf("XORKeyStream() = %x, want %x", gotPlaintext, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestStreams_matchCryptoCipher(t *testing.T) {
+	key, iv := random.Bytes(16), random.Bytes(16)
+	block, _ := aes.NewCipher(key)
+	plaintext := random.Bytes(100)
+
+	tests := []struct {
+		name string
+		got  cipher.Stream
+		want cipher.Stream
+	}{
+		{"cfb", NewCFBEncrypter(block, iv, block.BlockSize()), cipher.NewCFBEncrypter(block, iv)},
+		{"ofb", NewOFB(block, iv), cipher.NewOFB(block, iv)},
+		{"ctr", NewCTR(block, iv, ctr.BigEndian128), cipher.NewCTR(block, iv)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, want := make([]byte, len(plaintext)), make([]byte, len(plaintext))
+			tt.got.XORKeyStream(got, plaintext)
+			tt.want.XORKeyStream(want, plaintext)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("XORKeyStream() = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+// mode builds a matching encrypter and decrypter, as functions over whole messages.
+type mode struct {
+	name    string
+	encrypt func(b Block, iv, in []byte) []byte
+	decrypt func(b Block, iv, in []byte) []byte
+}
+
+func blockMode(newMode func(Block, []byte) cipher.BlockMode) func(b Block, iv, in []byte) []byte {
+	return func(b Block, iv, in []byte) []byte {
+		out := make([]byte, len(in))
+		newMode(b, iv).CryptBlocks(out, in)
+		return out
+	}
+}
+
+func streamMode(newStream func(Block, []byte) cipher.Stream) func(b Block, iv, in []byte) []byte {
+	return func(b Block, iv, in []byte) []byte {
+		out := make([]byte, len(in))
+		newStream(b, iv).XORKeyStream(out, in)
+		return out
+	}
+}
+
+var allModes = []mode{
+	{"cbc", blockMode(NewCBCEncrypter), blockMode(NewCBCDecrypter)},
+	{"pcbc", blockMode(NewPCBCEncrypter), blockMode(NewPCBCDecrypter)},
+	{
+		"cfb",
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCFBEncrypter(b, iv, b.BlockSize()) }),
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCFBDecrypter(b, iv, b.BlockSize()) }),
+	},
+	{
+		"cfb8",
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCFBEncrypter(b, iv, 1) }),
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCFBDecrypter(b, iv, 1) }),
+	},
+	{"ofb", streamMode(NewOFB), streamMode(NewOFB)},
+	{
+		"ctr",
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCTR(b, iv, ctr.BigEndian128) }),
+		streamMode(func(b Block, iv []byte) cipher.Stream { return NewCTR(b, iv, ctr.BigEndian128) }),
+	},
+}
+
+func TestModes_roundTrip(t *testing.T) {
+	toy32, _ := NewToyCipher(random.Bytes(8), 4)
+	toy64, _ := NewToyCipher(random.Bytes(8), 8)
+	aesBlock, _ := ecb.NewCipher(random.Bytes(16))
+
+	for _, b := range []Block{toy32, toy64, aesBlock} {
+		for _, m := range allModes {
+			t.Run(m.name, func(t *testing.T) {
+				iv := random.Bytes(b.BlockSize())
+				plaintext := random.Bytes(8 * b.BlockSize())
+
+				ciphertext := m.encrypt(b, iv, plaintext)
+				if reflect.DeepEqual(ciphertext, plaintext) {
+					t.Errorf("encrypt() did nothing")
+				}
+				if got := m.decrypt(b, iv, ciphertext); !reflect.DeepEqual(got, plaintext) {
+					t.Errorf("decrypt() = %x, want %x", got, plaintext)
+				}
+			})
+		}
+	}
+}
+
+func TestModes_errorPropagation(t *testing.T) {
+	toy, _ := NewToyCipher([]byte("YELLOW SUBMARINE"), 8)
+	blockSize := toy.BlockSize()
+
+	// number of plaintext bytes garbled by flipping one bit in the second of four ciphertext blocks
+	tests := []struct {
+		mode          string
+		wantMinBytes  int
+		wantMaxBytes  int
+		wantAllBlocks bool
+	}{
+		{"cbc", blockSize/2 + 1, blockSize + 1, false},
+		{"pcbc", 2 * blockSize, 3 * blockSize, true},
+		{"cfb", blockSize/2 + 1, blockSize + 1, false},
+		{"cfb8", blockSize/2 + 1, blockSize + 1, false},
+		{"ofb", 1, 1, false},
+		{"ctr", 1, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var m mode
+			for _, candidate := range allModes {
+				if candidate.name == tt.mode {
+					m = candidate
+				}
+			}
+
+			iv := random.Bytes(blockSize)
+			plaintext := make([]byte, 4*blockSize)
+			ciphertext := m.encrypt(toy, iv, plaintext)
+			ciphertext[blockSize] ^= 0x01
+
+			decrypted := m.decrypt(toy, iv, ciphertext)
+			garbled := 0
+			for i := range decrypted {
+				if decrypted[i] != plaintext[i] {
+					garbled++
+				}
+			}
+
+			if garbled < tt.wantMinBytes || garbled > tt.wantMaxBytes {
+				t.Errorf("%d bytes garbled, want %d to %d", garbled, tt.wantMinBytes, tt.wantMaxBytes)
+			}
+			if lastBlockGarbled := !reflect.DeepEqual(decrypted[3*blockSize:], plaintext[3*blockSize:]); lastBlockGarbled != tt.wantAllBlocks {
+				t.Errorf("last block garbled = %v, want %v", lastBlockGarbled, tt.wantAllBlocks)
+			}
+		})
+	}
+}
+
+func TestNewToyCipher(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       []byte
+		blockSize int
+		wantErr   bool
+	}{
+		{"32_bit", []byte("key"), 4, false},
+		{"64_bit", []byte("key"), 8, false},
+		{"bad_block_size", []byte("key"), 16, true},
+		{"empty_key", []byte{}, 8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewToyCipher(tt.key, tt.blockSize)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewToyCipher() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			plaintext := random.Bytes(tt.blockSize)
+			ciphertext, decrypted := make([]byte, tt.blockSize), make([]byte, tt.blockSize)
+			c.Encrypt(ciphertext, plaintext)
+			c.Decrypt(decrypted, ciphertext)
+			if !reflect.DeepEqual(decrypted, plaintext) {
+				t.Errorf("ToyCipher.Decrypt() = %x, want %x", decrypted, plaintext)
+			}
+		})
+	}
+}
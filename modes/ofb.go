@@ -0,0 +1,38 @@
+package modes
+
+import (
+	"crypto/cipher"
+)
+
+// ofb is a cipher.Stream for OFB mode. The keystream is the iv encrypted
+// over and over, so it never depends on the plaintext or ciphertext.
+// NIST SP 800-38A, 6.4
+type ofb struct {
+	b        Block
+	register []byte
+	out      []byte // unused keystream from the last block
+}
+
+// NewOFB returns an OFB stream, which is the same for encryption and decryption.
+func NewOFB(b Block, iv []byte) cipher.Stream {
+	return &ofb{b: b, register: checkIV(b, iv, "ofb")}
+}
+
+// XORKeyStream encrypts or decrypts src into dst.
+func (x *ofb) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("ofb: output smaller than input")
+	}
+
+	for i := range src {
+		if len(x.out) == 0 {
+			next := make([]byte, x.b.BlockSize())
+			x.b.Encrypt(next, x.register)
+			x.register = next
+			x.out = next
+		}
+
+		dst[i] = src[i] ^ x.out[0]
+		x.out = x.out[1:]
+	}
+}
@@ -0,0 +1,80 @@
+package modes
+
+import (
+	"crypto/cipher"
+
+	"github.com/adavidalbertson/cryptopals/xor"
+)
+
+// pcbc holds the state for propagating CBC mode, where each block is
+// chained with both the previous plaintext and ciphertext blocks. A
+// corrupted ciphertext block garbles every block after it.
+type pcbc struct {
+	b         Block
+	blockSize int
+	chain     []byte // previous plaintext XOR previous ciphertext, initially the iv
+}
+
+type pcbcEncrypter pcbc
+
+// NewPCBCEncrypter returns a cipher.BlockMode which encrypts in PCBC mode.
+func NewPCBCEncrypter(b Block, iv []byte) cipher.BlockMode {
+	return &pcbcEncrypter{b, b.BlockSize(), checkIV(b, iv, "pcbc")}
+}
+
+// BlockSize returns the block size of the underlying cipher.
+func (x *pcbcEncrypter) BlockSize() int {
+	return x.blockSize
+}
+
+// CryptBlocks encrypts src into dst, which may overlap entirely.
+func (x *pcbcEncrypter) CryptBlocks(dst, src []byte) {
+	if len(src)%x.blockSize != 0 {
+		panic("pcbc: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("pcbc: output smaller than input")
+	}
+
+	for i := 0; i < len(src); i += x.blockSize {
+		plaintextBlock := make([]byte, x.blockSize)
+		copy(plaintextBlock, src[i:i+x.blockSize])
+
+		diff, _ := xor.Xor(plaintextBlock, x.chain)
+		x.b.Encrypt(dst[i:i+x.blockSize], diff)
+		x.chain, _ = xor.Xor(plaintextBlock, dst[i:i+x.blockSize])
+	}
+}
+
+type pcbcDecrypter pcbc
+
+// NewPCBCDecrypter returns a cipher.BlockMode which decrypts in PCBC mode.
+func NewPCBCDecrypter(b Block, iv []byte) cipher.BlockMode {
+	return &pcbcDecrypter{b, b.BlockSize(), checkIV(b, iv, "pcbc")}
+}
+
+// BlockSize returns the block size of the underlying cipher.
+func (x *pcbcDecrypter) BlockSize() int {
+	return x.blockSize
+}
+
+// CryptBlocks decrypts src into dst, which may overlap entirely.
+func (x *pcbcDecrypter) CryptBlocks(dst, src []byte) {
+	if len(src)%x.blockSize != 0 {
+		panic("pcbc: input not full blocks")
+	}
+	if len(dst) < len(src) {
+		panic("pcbc: output smaller than input")
+	}
+
+	for i := 0; i < len(src); i += x.blockSize {
+		ciphertextBlock := make([]byte, x.blockSize)
+		copy(ciphertextBlock, src[i:i+x.blockSize])
+
+		decrypted := make([]byte, x.blockSize)
+		x.b.Decrypt(decrypted, ciphertextBlock)
+		plaintextBlock, _ := xor.Xor(decrypted, x.chain)
+		copy(dst[i:i+x.blockSize], plaintextBlock)
+		x.chain, _ = xor.Xor(plaintextBlock, ciphertextBlock)
+	}
+}
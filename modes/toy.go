@@ -0,0 +1,110 @@
+package modes
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const toyRounds = 8
+
+// ToyCipher is a small Feistel cipher with a 32 or 64-bit block, for fast
+// experiments with the modes. It is NOT secure: the point is that short
+// blocks make things like birthday-bound collisions easy to observe.
+type ToyCipher struct {
+	blockSize int
+	roundKeys [toyRounds]uint32
+}
+
+// NewToyCipher returns a ToyCipher with a 4 or 8-byte block size.
+// Any non-empty key is accepted.
+func NewToyCipher(key []byte, blockSize int) (*ToyCipher, error) {
+	if blockSize != 4 && blockSize != 8 {
+		return nil, fmt.Errorf("Toy cipher block size must be 4 or 8 bytes, got %d", blockSize)
+	}
+
+	if len(key) == 0 {
+		return nil, fmt.Errorf("Toy cipher key is empty")
+	}
+
+	c := &ToyCipher{blockSize: blockSize}
+
+	// FNV-style mixing of the key into each round key
+	h := uint32(2166136261)
+	for i := range c.roundKeys {
+		for _, k := range key {
+			h ^= uint32(k)
+			h *= 16777619
+		}
+		h ^= uint32(i)
+		c.roundKeys[i] = h
+	}
+
+	return c, nil
+}
+
+// BlockSize returns the block size in bytes.
+func (c *ToyCipher) BlockSize() int {
+	return c.blockSize
+}
+
+// Encrypt encrypts the first block of src into dst.
+func (c *ToyCipher) Encrypt(dst, src []byte) {
+	left, right := c.load(src)
+	for i := 0; i < toyRounds; i++ {
+		left, right = right, left^c.round(right, c.roundKeys[i])
+	}
+	c.store(dst, left, right)
+}
+
+// Decrypt decrypts the first block of src into dst.
+func (c *ToyCipher) Decrypt(dst, src []byte) {
+	left, right := c.load(src)
+	for i := toyRounds - 1; i >= 0; i-- {
+		left, right = right^c.round(left, c.roundKeys[i]), left
+	}
+	c.store(dst, left, right)
+}
+
+// round is the Feistel function, restricted to the width of a half block.
+func (c *ToyCipher) round(half, key uint32) uint32 {
+	x := half ^ key
+	x *= 0x9E3779B1
+	x ^= x>>15 | x<<17
+
+	return x & c.halfMask()
+}
+
+func (c *ToyCipher) halfMask() uint32 {
+	if c.blockSize == 4 {
+		return 0xFFFF
+	}
+
+	return 0xFFFFFFFF
+}
+
+func (c *ToyCipher) load(src []byte) (left, right uint32) {
+	if len(src) < c.blockSize {
+		panic("toy: input not full block")
+	}
+
+	if c.blockSize == 4 {
+		return uint32(binary.BigEndian.Uint16(src[:2])), uint32(binary.BigEndian.Uint16(src[2:4]))
+	}
+
+	return binary.BigEndian.Uint32(src[:4]), binary.BigEndian.Uint32(src[4:8])
+}
+
+func (c *ToyCipher) store(dst []byte, left, right uint32) {
+	if len(dst) < c.blockSize {
+		panic("toy: output not full block")
+	}
+
+	if c.blockSize == 4 {
+		binary.BigEndian.PutUint16(dst[:2], uint16(left))
+		binary.BigEndian.PutUint16(dst[2:4], uint16(right))
+		return
+	}
+
+	binary.BigEndian.PutUint32(dst[:4], left)
+	binary.BigEndian.PutUint32(dst[4:8], right)
+}
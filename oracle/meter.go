@@ -0,0 +1,302 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by a metered oracle once its query budget is spent.
+var ErrBudgetExceeded = errors.New("Oracle query budget exceeded")
+
+// ErrInjectedFailure is returned by a metered oracle when it fails a query on purpose.
+var ErrInjectedFailure = errors.New("Injected oracle failure")
+
+// Exchange is one query to a metered oracle, as recorded in its transcript.
+// Byte slices are copies, so they stay valid if the attack reuses its buffers.
+type Exchange struct {
+	Method   string
+	Args     []interface{}
+	Result   interface{}
+	Err      error
+	Duration time.Duration
+}
+
+// Stats summarizes the queries made through a Meter.
+type Stats struct {
+	Queries  int
+	Failures int
+	Elapsed  time.Duration
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d queries (%d failed) in %v", s.Queries, s.Failures, s.Elapsed)
+}
+
+// Meter counts and controls the queries made to the oracles it wraps.
+// Oracles wrapped by the same Meter share its count and budget, so an attack
+// that uses several oracles is measured as a whole.
+// The zero value only counts; set the exported fields before use to add
+// latency, failures, a budget, or a transcript.
+type Meter struct {
+	// Latency is added to every query.
+	Latency time.Duration
+	// FailureRate is the probability, from 0 to 1, that a query fails with
+	// ErrInjectedFailure instead of reaching the oracle.
+	FailureRate float64
+	// Budget is the maximum number of queries. Zero means unlimited.
+	Budget int
+	// Record keeps a transcript of every query.
+	Record bool
+
+	mu          sync.Mutex
+	queries     int
+	failures    int
+	first, last time.Time
+	transcript  []Exchange
+}
+
+// Stats returns the number of queries so far, and the wall time from the
+// start of the first query to the end of the last.
+func (m *Meter) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Stats{m.queries, m.failures, m.last.Sub(m.first)}
+}
+
+// Queries returns the number of queries so far, including failed ones.
+func (m *Meter) Queries() int {
+	return m.Stats().Queries
+}
+
+// Transcript returns the recorded queries, oldest first.
+func (m *Meter) Transcript() []Exchange {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Exchange(nil), m.transcript...)
+}
+
+// Reset clears the counts and transcript, and restores the full budget.
+func (m *Meter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queries, m.failures = 0, 0
+	m.first, m.last = time.Time{}, time.Time{}
+	m.transcript = nil
+}
+
+// query runs one call to a wrapped oracle, applying the budget, latency,
+// and failure rate, and records the result.
+func (m *Meter) query(method string, args []interface{}, call func() (interface{}, error)) error {
+	start := time.Now()
+
+	m.mu.Lock()
+	if m.Budget > 0 && m.queries >= m.Budget {
+		m.mu.Unlock()
+		return ErrBudgetExceeded
+	}
+	m.queries++
+	if m.first.IsZero() {
+		m.first = start
+	}
+	fail := m.FailureRate > 0 && rand.Float64() < m.FailureRate
+	if fail {
+		m.failures++
+	}
+	m.mu.Unlock()
+
+	time.Sleep(m.Latency)
+
+	var result interface{}
+	err := ErrInjectedFailure
+	if !fail {
+		result, err = call()
+	}
+
+	end := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if end.After(m.last) {
+		m.last = end
+	}
+	if m.Record {
+		m.transcript = append(m.transcript, Exchange{method, copyAll(args), copyValue(result), err, end.Sub(start)})
+	}
+
+	return err
+}
+
+func copyValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+
+	return v
+}
+
+func copyAll(vs []interface{}) []interface{} {
+	copies := make([]interface{}, len(vs))
+	for i, v := range vs {
+		copies[i] = copyValue(v)
+	}
+
+	return copies
+}
+
+// EncryptionOracle wraps o so that its queries go through m.
+func (m *Meter) EncryptionOracle(o EncryptionOracle) EncryptionOracle {
+	return meteredEncryptionOracle{m, o}
+}
+
+// DecryptionOracle wraps o so that its queries go through m.
+func (m *Meter) DecryptionOracle(o DecryptionOracle) DecryptionOracle {
+	return meteredDecryptionOracle{m, o}
+}
+
+// PaddingValidator wraps o so that its queries go through m.
+func (m *Meter) PaddingValidator(o PaddingValidator) PaddingValidator {
+	return meteredPaddingValidator{m, o}
+}
+
+// AdminChecker wraps o so that its queries go through m.
+func (m *Meter) AdminChecker(o AdminChecker) AdminChecker {
+	return meteredAdminChecker{m, o}
+}
+
+// EditOracle wraps o so that its queries go through m.
+func (m *Meter) EditOracle(o EditOracle) EditOracle {
+	return meteredEditOracle{m, o}
+}
+
+// ProfileOracle wraps o so that its queries go through m.
+func (m *Meter) ProfileOracle(o ProfileOracle) ProfileOracle {
+	return meteredProfileOracle{m, o}
+}
+
+// MacSigner wraps o so that its queries go through m.
+func (m *Meter) MacSigner(o MacSigner) MacSigner {
+	return meteredMacSigner{m, o}
+}
+
+// MacVerifier wraps o so that its queries go through m.
+func (m *Meter) MacVerifier(o MacVerifier) MacVerifier {
+	return meteredMacVerifier{m, o}
+}
+
+type meteredEncryptionOracle struct {
+	m *Meter
+	o EncryptionOracle
+}
+
+func (w meteredEncryptionOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	err = w.m.query("Encrypt", []interface{}{plaintext}, func() (interface{}, error) {
+		ciphertext, err = w.o.Encrypt(plaintext)
+		return ciphertext, err
+	})
+
+	return
+}
+
+type meteredDecryptionOracle struct {
+	m *Meter
+	o DecryptionOracle
+}
+
+func (w meteredDecryptionOracle) Decrypt(ciphertext []byte) (plaintext []byte, err error) {
+	err = w.m.query("Decrypt", []interface{}{ciphertext}, func() (interface{}, error) {
+		plaintext, err = w.o.Decrypt(ciphertext)
+		return plaintext, err
+	})
+
+	return
+}
+
+type meteredPaddingValidator struct {
+	m *Meter
+	o PaddingValidator
+}
+
+func (w meteredPaddingValidator) Validate(ciphertext, iv []byte) (valid bool, err error) {
+	err = w.m.query("Validate", []interface{}{ciphertext, iv}, func() (interface{}, error) {
+		valid, err = w.o.Validate(ciphertext, iv)
+		return valid, err
+	})
+
+	return
+}
+
+type meteredAdminChecker struct {
+	m *Meter
+	o AdminChecker
+}
+
+func (w meteredAdminChecker) Decrypt(ciphertext []byte) (isAdmin bool, err error) {
+	err = w.m.query("Decrypt", []interface{}{ciphertext}, func() (interface{}, error) {
+		isAdmin, err = w.o.Decrypt(ciphertext)
+		return isAdmin, err
+	})
+
+	return
+}
+
+type meteredEditOracle struct {
+	m *Meter
+	o EditOracle
+}
+
+func (w meteredEditOracle) Edit(ciphertext, newText []byte, offset int) (newCiphertext []byte, err error) {
+	err = w.m.query("Edit", []interface{}{ciphertext, newText, offset}, func() (interface{}, error) {
+		newCiphertext, err = w.o.Edit(ciphertext, newText, offset)
+		return newCiphertext, err
+	})
+
+	return
+}
+
+type meteredProfileOracle struct {
+	m *Meter
+	o ProfileOracle
+}
+
+func (w meteredProfileOracle) ProfileFor(email string) (token []byte, err error) {
+	err = w.m.query("ProfileFor", []interface{}{email}, func() (interface{}, error) {
+		token, err = w.o.ProfileFor(email)
+		return token, err
+	})
+
+	return
+}
+
+type meteredMacSigner struct {
+	m *Meter
+	o MacSigner
+}
+
+func (w meteredMacSigner) Sign(message []byte) (tag []byte, err error) {
+	err = w.m.query("Sign", []interface{}{message}, func() (interface{}, error) {
+		tag, err = w.o.Sign(message)
+		return tag, err
+	})
+
+	return
+}
+
+type meteredMacVerifier struct {
+	m *Meter
+	o MacVerifier
+}
+
+func (w meteredMacVerifier) Verify(message, tag []byte) (valid bool, err error) {
+	err = w.m.query("Verify", []interface{}{message, tag}, func() (interface{}, error) {
+		valid, err = w.o.Verify(message, tag)
+		return valid, err
+	})
+
+	return
+}
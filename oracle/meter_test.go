@@ -0,0 +1,140 @@
+package oracle
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// echo is an EncryptionOracle which returns its input.
+type echo struct{}
+
+func (echo) Encrypt(plaintext []byte) ([]byte, error) {
+	return plaintext, nil
+}
+
+// lastByte is a PaddingValidator which accepts a ciphertext ending in 0x01.
+type lastByte struct{}
+
+func (lastByte) Validate(ciphertext, iv []byte) (bool, error) {
+	return len(ciphertext) > 0 && ciphertext[len(ciphertext)-1] == 1, nil
+}
+
+func TestMeter_Queries(t *testing.T) {
+	m := &Meter{}
+	enc := m.EncryptionOracle(echo{})
+	val := m.PaddingValidator(lastByte{})
+
+	for i := 0; i < 3; i++ {
+		got, err := enc.Encrypt([]byte{byte(i)})
+		if err != nil || !reflect.DeepEqual(got, []byte{byte(i)}) {
+			t.Errorf("Encrypt() = %v, %v, want %v, <nil>", got, err, []byte{byte(i)})
+		}
+	}
+	if valid, _ := val.Validate([]byte{1}, nil); !valid {
+		t.Errorf("Validate() = false, want true")
+	}
+
+	if got := m.Queries(); got != 4 {
+		t.Errorf("Meter.Queries() = %v, want %v", got, 4)
+	}
+
+	m.Reset()
+	if got := m.Queries(); got != 0 {
+		t.Errorf("Meter.Queries() after Reset() = %v, want %v", got, 0)
+	}
+}
+
+func TestMeter_Budget(t *testing.T) {
+	m := &Meter{Budget: 2}
+	enc := m.EncryptionOracle(echo{})
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{"first", nil},
+		{"second", nil},
+		{"over_budget", ErrBudgetExceeded},
+		{"still_over_budget", ErrBudgetExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := enc.Encrypt(nil); err != tt.wantErr {
+				t.Errorf("Encrypt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := m.Queries(); got != 2 {
+		t.Errorf("Meter.Queries() = %v, want %v", got, 2)
+	}
+}
+
+func TestMeter_FailureRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		failureRate  float64
+		wantFailures int
+	}{
+		{"never", 0, 0},
+		{"always", 1, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Meter{FailureRate: tt.failureRate}
+			enc := m.EncryptionOracle(echo{})
+			failures := 0
+			for i := 0; i < 10; i++ {
+				if _, err := enc.Encrypt(nil); err == ErrInjectedFailure {
+					failures++
+				}
+			}
+
+			if failures != tt.wantFailures {
+				t.Errorf("%d injected failures, want %d", failures, tt.wantFailures)
+			}
+			if got := m.Stats().Failures; got != tt.wantFailures {
+				t.Errorf("Meter.Stats().Failures = %v, want %v", got, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestMeter_Latency(t *testing.T) {
+	m := &Meter{Latency: 5 * time.Millisecond}
+	enc := m.EncryptionOracle(echo{})
+	for i := 0; i < 3; i++ {
+		enc.Encrypt(nil)
+	}
+
+	if got := m.Stats().Elapsed; got < 15*time.Millisecond {
+		t.Errorf("Meter.Stats().Elapsed = %v, want at least %v", got, 15*time.Millisecond)
+	}
+}
+
+func TestMeter_Transcript(t *testing.T) {
+	m := &Meter{Record: true}
+	enc := m.EncryptionOracle(echo{})
+	val := m.PaddingValidator(lastByte{})
+
+	buf := []byte("YELLOW SUBMARINE")
+	enc.Encrypt(buf)
+	// the transcript must not change when the attack reuses its buffer
+	copy(buf, bytes.Repeat([]byte{0}, len(buf)))
+	val.Validate([]byte{1}, []byte{2})
+
+	want := []Exchange{
+		{Method: "Encrypt", Args: []interface{}{[]byte("YELLOW SUBMARINE")}, Result: []byte("YELLOW SUBMARINE")},
+		{Method: "Validate", Args: []interface{}{[]byte{1}, []byte{2}}, Result: true},
+	}
+
+	got := m.Transcript()
+	for i := range got {
+		got[i].Duration = 0
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Meter.Transcript() = %v, want %v", got, want)
+	}
+}
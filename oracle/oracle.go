@@ -0,0 +1,68 @@
+// Package oracle defines the interfaces the attacks use to talk to their
+// targets. Any type with the right method can be attacked, whether it's one
+// of the oracles in this repository, a mock, or a client for a real service.
+package oracle
+
+// EncryptionOracle encrypts attacker-chosen plaintext, typically after
+// adding secret data of its own.
+// Cryptopals Set 2, Challenges 12, 14, and 16
+type EncryptionOracle interface {
+	Encrypt(plaintext []byte) (ciphertext []byte, err error)
+}
+
+// ProfileOracle issues encrypted user profile tokens for chosen email addresses.
+// Cryptopals Set 2, Challenge 13
+// https://cryptopals.com/sets/2/challenges/13
+type ProfileOracle interface {
+	ProfileFor(email string) (token []byte, err error)
+}
+
+// DecryptionOracle decrypts attacker-chosen ciphertext.
+type DecryptionOracle interface {
+	Decrypt(ciphertext []byte) (plaintext []byte, err error)
+}
+
+// PaddingValidator reports whether a CBC ciphertext decrypts to correctly
+// padded plaintext. err is reserved for failures to get an answer at all,
+// not for bad padding.
+// Cryptopals Set 3, Challenge 17
+// https://cryptopals.com/sets/3/challenges/17
+type PaddingValidator interface {
+	Validate(ciphertext, iv []byte) (valid bool, err error)
+}
+
+// AdminChecker decrypts a user token and reports whether it grants admin rights.
+// Cryptopals Set 2, Challenge 16
+// https://cryptopals.com/sets/2/challenges/16
+type AdminChecker interface {
+	Decrypt(ciphertext []byte) (isAdmin bool, err error)
+}
+
+// CiphertextSource hands out a ciphertext to attack, with its iv.
+// Cryptopals Set 3, Challenge 17
+// https://cryptopals.com/sets/3/challenges/17
+type CiphertextSource interface {
+	Encrypt() (ciphertext, iv []byte, err error)
+}
+
+// EditOracle re-encrypts a ciphertext with newText spliced in at offset,
+// measured in blocks, without revealing the key.
+// Cryptopals Set 4, Challenge 25
+// https://cryptopals.com/sets/4/challenges/25
+type EditOracle interface {
+	Edit(ciphertext, newText []byte, offset int) (newCiphertext []byte, err error)
+}
+
+// MacSigner computes tags for messages under a key it keeps secret.
+// Cryptopals Set 4, Challenge 28
+// https://cryptopals.com/sets/4/challenges/28
+type MacSigner interface {
+	Sign(message []byte) (tag []byte, err error)
+}
+
+// MacVerifier checks tags computed by the matching MacSigner.
+// Cryptopals Set 4, Challenge 29
+// https://cryptopals.com/sets/4/challenges/29
+type MacVerifier interface {
+	Verify(message, tag []byte) (valid bool, err error)
+}
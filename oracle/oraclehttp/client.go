@@ -0,0 +1,130 @@
+package oraclehttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+// Endpoint is the URL of one oracle on a Server, and the client used to reach it.
+// If Client is nil, http.DefaultClient is used.
+type Endpoint struct {
+	URL    string
+	Client *http.Client
+}
+
+// post sends req to the endpoint and decodes the response into resp.
+func (e Endpoint) post(req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	r, err := client.Post(e.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.NewDecoder(r.Body).Decode(&e) == nil && e.Error != "" {
+			return fmt.Errorf("Oracle error: %s", e.Error)
+		}
+		return fmt.Errorf("Oracle returned %s", r.Status)
+	}
+
+	return json.NewDecoder(r.Body).Decode(resp)
+}
+
+// EncryptionOracle calls an endpoint served by HandleEncryptionOracle.
+type EncryptionOracle Endpoint
+
+var _ oracle.EncryptionOracle = EncryptionOracle{}
+
+// Encrypt implements oracle.EncryptionOracle.
+func (o EncryptionOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	var resp encryptResponse
+	err = Endpoint(o).post(encryptRequest{plaintext}, &resp)
+	return resp.Ciphertext, err
+}
+
+// DecryptionOracle calls an endpoint served by HandleDecryptionOracle.
+type DecryptionOracle Endpoint
+
+var _ oracle.DecryptionOracle = DecryptionOracle{}
+
+// Decrypt implements oracle.DecryptionOracle.
+func (o DecryptionOracle) Decrypt(ciphertext []byte) (plaintext []byte, err error) {
+	var resp decryptResponse
+	err = Endpoint(o).post(decryptRequest{ciphertext}, &resp)
+	return resp.Plaintext, err
+}
+
+// PaddingValidator calls an endpoint served by HandlePaddingValidator.
+type PaddingValidator Endpoint
+
+var _ oracle.PaddingValidator = PaddingValidator{}
+
+// Validate implements oracle.PaddingValidator.
+func (o PaddingValidator) Validate(ciphertext, iv []byte) (valid bool, err error) {
+	var resp validateResponse
+	err = Endpoint(o).post(validateRequest{ciphertext, iv}, &resp)
+	return resp.Valid, err
+}
+
+// AdminChecker calls an endpoint served by HandleAdminChecker.
+type AdminChecker Endpoint
+
+var _ oracle.AdminChecker = AdminChecker{}
+
+// Decrypt implements oracle.AdminChecker.
+func (o AdminChecker) Decrypt(ciphertext []byte) (isAdmin bool, err error) {
+	var resp adminResponse
+	err = Endpoint(o).post(decryptRequest{ciphertext}, &resp)
+	return resp.Admin, err
+}
+
+// CiphertextSource calls an endpoint served by HandleCiphertextSource.
+type CiphertextSource Endpoint
+
+var _ oracle.CiphertextSource = CiphertextSource{}
+
+// Encrypt implements oracle.CiphertextSource.
+func (o CiphertextSource) Encrypt() (ciphertext, iv []byte, err error) {
+	var resp ciphertextResponse
+	err = Endpoint(o).post(struct{}{}, &resp)
+	return resp.Ciphertext, resp.IV, err
+}
+
+// EditOracle calls an endpoint served by HandleEditOracle.
+type EditOracle Endpoint
+
+var _ oracle.EditOracle = EditOracle{}
+
+// Edit implements oracle.EditOracle.
+func (o EditOracle) Edit(ciphertext, newText []byte, offset int) (newCiphertext []byte, err error) {
+	var resp encryptResponse
+	err = Endpoint(o).post(editRequest{ciphertext, newText, offset}, &resp)
+	return resp.Ciphertext, err
+}
+
+// ProfileOracle calls an endpoint served by HandleProfileOracle.
+type ProfileOracle Endpoint
+
+var _ oracle.ProfileOracle = ProfileOracle{}
+
+// ProfileFor implements oracle.ProfileOracle.
+func (o ProfileOracle) ProfileFor(email string) (token []byte, err error) {
+	var resp profileResponse
+	err = Endpoint(o).post(profileRequest{email}, &resp)
+	return resp.Token, err
+}
@@ -0,0 +1,155 @@
+package oraclehttp
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/aes/cbc"
+	"github.com/adavidalbertson/cryptopals/aes/ctr"
+	"github.com/adavidalbertson/cryptopals/aes/ecb"
+	"github.com/adavidalbertson/cryptopals/attacks"
+	"github.com/adavidalbertson/cryptopals/padding"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+const challenge12Suffix = "Um9sbGluJyBpbiBteSA1LjAKV2l0aCBteSByYWctdG9wIGRvd24gc28gbXkgaGFpciBjYW4gYmxvdwpUaGUgZ2lybGllcyBvbiBzdGFuZGJ5IHdhdmluZyBqdXN0IHRvIHNheSBoaQpEaWQgeW91IHN0b3A/IE5vLCBJIGp1c3QgZHJvdmUgYnkK"
+
+func TestAttacksOverHTTP(t *testing.T) {
+	suffix, _ := base64.StdEncoding.DecodeString(challenge12Suffix)
+	ecbOracle, _ := ecb.NewAesEcbOracle(suffix, true)
+	cbcOracle := cbc.NewAesCbcOracle()
+	paddingOracle := cbc.NewPaddingOracle()
+	keyAsIvOracle := cbc.NewKeyAsIvOracle()
+	plaintext := []byte("Burning 'em, if you ain't quick and nimble")
+	ctrCipher, _ := ctr.NewAesCtrCipher(random.Bytes(16), random.Bytes(8))
+	ctrCiphertext, _ := ctrCipher.Encrypt(plaintext)
+
+	s := NewServer()
+	s.HandleEncryptionOracle("/ecb", ecbOracle)
+	s.HandleEncryptionOracle("/cbc/token", cbcOracle)
+	s.HandleAdminChecker("/cbc/admin", cbcOracle)
+	s.HandleEncryptionOracle("/keyiv/token", keyAsIvOracle)
+	s.HandleAdminChecker("/keyiv/admin", keyAsIvOracle)
+	s.HandleCiphertextSource("/padding/ciphertext", paddingOracle)
+	s.HandlePaddingValidator("/padding/validate", paddingOracle)
+	s.HandleEditOracle("/ctr/edit", &ctrCipher)
+	s.HandleProfileOracle("/profile", ecb.NewProfileMaker())
+
+	server := httptest.NewServer(s)
+	defer server.Close()
+	endpoint := func(path string) Endpoint {
+		return Endpoint{server.URL + path, server.Client()}
+	}
+
+	t.Run("ecb_suffix", func(t *testing.T) {
+		got, err := attacks.AesEcbOracleBreak(EncryptionOracle(endpoint("/ecb")))
+		if err != nil {
+			t.Errorf("AesEcbOracleBreak() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, suffix) {
+			t.Errorf("AesEcbOracleBreak() = %q, want %q", got, suffix)
+		}
+	})
+
+	t.Run("cbc_bitflip", func(t *testing.T) {
+		token, err := attacks.AesCbcOracleBreak(EncryptionOracle(endpoint("/cbc/token")))
+		if err != nil {
+			t.Errorf("AesCbcOracleBreak() error = %v", err)
+			return
+		}
+		isAdmin, err := AdminChecker(endpoint("/cbc/admin")).Decrypt(token)
+		if err != nil || !isAdmin {
+			t.Errorf("AdminChecker.Decrypt() = %v, %v, want true, <nil>", isAdmin, err)
+		}
+	})
+
+	t.Run("cbc_key_as_iv", func(t *testing.T) {
+		// the plaintext only reaches the attack in the text of the error
+		key, err := attacks.KeyAsIvAttack(EncryptionOracle(endpoint("/keyiv/token")), AdminChecker(endpoint("/keyiv/admin")))
+		if err != nil {
+			t.Errorf("KeyAsIvAttack() error = %v", err)
+			return
+		}
+		plaintext, _ := padding.Pkcs7([]byte("userdata=x;admin=true"), 16)
+		token, _ := cbc.Encrypt(plaintext, key, key)
+		isAdmin, err := AdminChecker(endpoint("/keyiv/admin")).Decrypt(token)
+		if err != nil || !isAdmin {
+			t.Errorf("AdminChecker.Decrypt() of forged token = %v, %v, want true, <nil>", isAdmin, err)
+		}
+	})
+
+	t.Run("cbc_padding", func(t *testing.T) {
+		ciphertext, iv, err := CiphertextSource(endpoint("/padding/ciphertext")).Encrypt()
+		if err != nil {
+			t.Errorf("CiphertextSource.Encrypt() error = %v", err)
+			return
+		}
+		got, err := attacks.PaddingOracleAttack(PaddingValidator(endpoint("/padding/validate")), ciphertext, iv)
+		if err != nil {
+			t.Errorf("PaddingOracleAttack() error = %v", err)
+			return
+		}
+		if !strings.HasPrefix(string(got), "00000") {
+			t.Errorf("PaddingOracleAttack() = %q, want a Challenge 17 plaintext", got)
+		}
+	})
+
+	t.Run("ctr_edit", func(t *testing.T) {
+		got, err := attacks.BreakCtrEdit(EditOracle(endpoint("/ctr/edit")), ctrCiphertext)
+		if err != nil {
+			t.Errorf("BreakCtrEdit() error = %v", err)
+			return
+		}
+		if !reflect.DeepEqual(got, plaintext) {
+			t.Errorf("BreakCtrEdit() = %q, want %q", got, plaintext)
+		}
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		if _, err := attacks.ProfileSpoofAdmin(ProfileOracle(endpoint("/profile"))); err != nil {
+			t.Errorf("ProfileSpoofAdmin() error = %v", err)
+		}
+	})
+}
+
+func TestServer_errors(t *testing.T) {
+	s := NewServer()
+	s.HandleEditOracle("/ctr/edit", &ctr.AesCtrCipher{})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{"wrong_method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"bad_json", http.MethodPost, "{", http.StatusBadRequest},
+		{"oracle_error", http.MethodPost, `{"ciphertext":"AAAA","newText":"AAAA","offset":5}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+"/ctr/edit", strings.NewReader(tt.body))
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Errorf("Do() error = %v", err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	_, err := EditOracle{server.URL + "/ctr/edit", server.Client()}.Edit([]byte{0}, []byte{0}, 5)
+	if err == nil {
+		t.Errorf("EditOracle.Edit() error = <nil>, want oracle error")
+	}
+}
@@ -0,0 +1,174 @@
+// Package oraclehttp serves oracles over HTTP, and provides clients which
+// implement the oracle interfaces by calling a server. The attacks run
+// unchanged against a client, so they can be pointed at a URL.
+//
+// Every endpoint takes a POST with a JSON request body and returns a JSON
+// response. Byte slices are base64 encoded, as encoding/json does by default.
+// If the oracle returns an error, the response has status 422 and the
+// error message in the "error" field.
+package oraclehttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+)
+
+type encryptRequest struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+type encryptResponse struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type decryptRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type decryptResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+type validateRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+	IV         []byte `json:"iv"`
+}
+
+type validateResponse struct {
+	Valid bool `json:"valid"`
+}
+
+type adminResponse struct {
+	Admin bool `json:"admin"`
+}
+
+type ciphertextResponse struct {
+	Ciphertext []byte `json:"ciphertext"`
+	IV         []byte `json:"iv"`
+}
+
+type editRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+	NewText    []byte `json:"newText"`
+	Offset     int    `json:"offset"`
+}
+
+type profileRequest struct {
+	Email string `json:"email"`
+}
+
+type profileResponse struct {
+	Token []byte `json:"token"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server is an http.Handler which exposes oracles at the paths they are registered on.
+type Server struct {
+	mux *http.ServeMux
+}
+
+// NewServer returns a Server with no oracles registered.
+func NewServer() *Server {
+	return &Server{http.NewServeMux()}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle registers a POST endpoint which decodes the request into req, then
+// encodes the result of call as the response.
+func (s *Server) handle(path string, newRequest func() interface{}, call func(req interface{}) (interface{}, error)) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		req := newRequest()
+		if req != nil {
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+				return
+			}
+		}
+
+		resp, err := call(req)
+		if err != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, errorResponse{err.Error()})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// HandleEncryptionOracle serves o at path.
+func (s *Server) HandleEncryptionOracle(path string, o oracle.EncryptionOracle) {
+	s.handle(path, func() interface{} { return &encryptRequest{} }, func(req interface{}) (interface{}, error) {
+		ciphertext, err := o.Encrypt(req.(*encryptRequest).Plaintext)
+		return encryptResponse{ciphertext}, err
+	})
+}
+
+// HandleDecryptionOracle serves o at path.
+func (s *Server) HandleDecryptionOracle(path string, o oracle.DecryptionOracle) {
+	s.handle(path, func() interface{} { return &decryptRequest{} }, func(req interface{}) (interface{}, error) {
+		plaintext, err := o.Decrypt(req.(*decryptRequest).Ciphertext)
+		return decryptResponse{plaintext}, err
+	})
+}
+
+// HandlePaddingValidator serves o at path.
+func (s *Server) HandlePaddingValidator(path string, o oracle.PaddingValidator) {
+	s.handle(path, func() interface{} { return &validateRequest{} }, func(req interface{}) (interface{}, error) {
+		r := req.(*validateRequest)
+		valid, err := o.Validate(r.Ciphertext, r.IV)
+		return validateResponse{valid}, err
+	})
+}
+
+// HandleAdminChecker serves o at path.
+func (s *Server) HandleAdminChecker(path string, o oracle.AdminChecker) {
+	s.handle(path, func() interface{} { return &decryptRequest{} }, func(req interface{}) (interface{}, error) {
+		isAdmin, err := o.Decrypt(req.(*decryptRequest).Ciphertext)
+		return adminResponse{isAdmin}, err
+	})
+}
+
+// HandleCiphertextSource serves o at path. The request body is ignored.
+func (s *Server) HandleCiphertextSource(path string, o oracle.CiphertextSource) {
+	s.handle(path, func() interface{} { return nil }, func(interface{}) (interface{}, error) {
+		ciphertext, iv, err := o.Encrypt()
+		return ciphertextResponse{ciphertext, iv}, err
+	})
+}
+
+// HandleEditOracle serves o at path.
+func (s *Server) HandleEditOracle(path string, o oracle.EditOracle) {
+	s.handle(path, func() interface{} { return &editRequest{} }, func(req interface{}) (interface{}, error) {
+		r := req.(*editRequest)
+		ciphertext, err := o.Edit(r.Ciphertext, r.NewText, r.Offset)
+		return encryptResponse{ciphertext}, err
+	})
+}
+
+// HandleProfileOracle serves o at path.
+func (s *Server) HandleProfileOracle(path string, o oracle.ProfileOracle) {
+	s.handle(path, func() interface{} { return &profileRequest{} }, func(req interface{}) (interface{}, error) {
+		token, err := o.ProfileFor(req.(*profileRequest).Email)
+		return profileResponse{token}, err
+	})
+}
@@ -1,10 +1,59 @@
 package padding
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
+
+	"github.com/adavidalbertson/cryptopals/random"
 )
 
+// Padder pads messages to a multiple of the block size, and removes that
+// padding again. Unpad returns a typed error on failure, which never
+// includes the message itself.
+type Padder interface {
+	Pad(partial []byte, blockSize int) (padded []byte, err error)
+	Unpad(padded []byte) (unpadded []byte, err error)
+}
+
+// ErrEmpty is returned when unpadding an empty message.
+var ErrEmpty = errors.New("Cannot unpad an empty message")
+
+// ErrBlockSize is returned when the block size can't be represented in a
+// single padding byte.
+var ErrBlockSize = errors.New("Block size must be between 1 and 255 bytes (https://tools.ietf.org/html/rfc2315#section-10.3)")
+
+// InvalidPaddingError is returned when a message's padding is malformed.
+// It names the scheme, but deliberately leaves out the message.
+type InvalidPaddingError struct {
+	Scheme string
+}
+
+func (e InvalidPaddingError) Error() string {
+	return fmt.Sprintf("Invalid %s padding", e.Scheme)
+}
+
+func validateBlockSize(blockSize int) error {
+	if blockSize <= 0 || blockSize >= 256 {
+		return ErrBlockSize
+	}
+
+	return nil
+}
+
+// Pkcs7Padder pads with n bytes of value n.
+// https://tools.ietf.org/html/rfc2315#section-10.3
+type Pkcs7Padder struct{}
+
+// Pad calls Pkcs7.
+func (Pkcs7Padder) Pad(partial []byte, blockSize int) ([]byte, error) {
+	return Pkcs7(partial, blockSize)
+}
+
+// Unpad calls Pkcs7Unpad.
+func (Pkcs7Padder) Unpad(padded []byte) ([]byte, error) {
+	return Pkcs7Unpad(padded)
+}
+
 // Pkcs7 pads messages to match a given blockSize according to RF 2315.
 // https://tools.ietf.org/html/rfc2315#section-10.3
 // Cryptopals Set 1, Challenge 9
@@ -12,8 +61,7 @@ import (
 func Pkcs7(partial []byte, blockSize int) (padded []byte, err error) {
 	padded = partial
 
-	if blockSize >= 256 {
-		err = fmt.Errorf("Block size must be less than 256 bytes (https://tools.ietf.org/html/rfc2315#section-10.3)")
+	if err = validateBlockSize(blockSize); err != nil {
 		return
 	}
 
@@ -30,13 +78,157 @@ func Pkcs7(partial []byte, blockSize int) (padded []byte, err error) {
 // Cryptopals Set 1, Challenge 9
 // https://cryptopals.com/sets/1/challenges/9
 func Pkcs7Unpad(padded []byte) (unpadded []byte, err error) {
-	p := padded[len(padded)-1]
+	if len(padded) == 0 {
+		return make([]byte, 0), ErrEmpty
+	}
+
+	p := int(padded[len(padded)-1])
+	if p == 0 || p > len(padded) {
+		return make([]byte, 0), InvalidPaddingError{"PKCS#7"}
+	}
+
+	for _, b := range padded[len(padded)-p:] {
+		if int(b) != p {
+			return make([]byte, 0), InvalidPaddingError{"PKCS#7"}
+		}
+	}
+
+	return padded[:len(padded)-p], nil
+}
+
+// AnsiX923Padder pads with n-1 zero bytes followed by a byte of value n.
+type AnsiX923Padder struct{}
+
+// Pad pads the message according to ANSI X9.23.
+func (AnsiX923Padder) Pad(partial []byte, blockSize int) (padded []byte, err error) {
+	padded = partial
+	if err = validateBlockSize(blockSize); err != nil {
+		return
+	}
+
+	p := blockSize - (len(padded) % blockSize)
+	padded = append(padded, make([]byte, p-1)...)
+	padded = append(padded, byte(p))
+
+	return
+}
+
+// Unpad removes ANSI X9.23 padding, checking that the filler bytes are zero.
+func (AnsiX923Padder) Unpad(padded []byte) (unpadded []byte, err error) {
+	if len(padded) == 0 {
+		return make([]byte, 0), ErrEmpty
+	}
+
+	p := int(padded[len(padded)-1])
+	if p == 0 || p > len(padded) {
+		return make([]byte, 0), InvalidPaddingError{"ANSI X9.23"}
+	}
+
+	for _, b := range padded[len(padded)-p : len(padded)-1] {
+		if b != 0 {
+			return make([]byte, 0), InvalidPaddingError{"ANSI X9.23"}
+		}
+	}
+
+	return padded[:len(padded)-p], nil
+}
 
-	unpadded = bytes.TrimRight(padded, string(p))
+// Iso10126Padder pads with n-1 random bytes followed by a byte of value n.
+type Iso10126Padder struct{}
 
-	if len(padded)-len(unpadded) != int(p) {
-		return make([]byte, 0), fmt.Errorf("Invalid padding: %s", string(padded))
+// Pad pads the message according to ISO 10126.
+func (Iso10126Padder) Pad(partial []byte, blockSize int) (padded []byte, err error) {
+	padded = partial
+	if err = validateBlockSize(blockSize); err != nil {
+		return
+	}
+
+	p := blockSize - (len(padded) % blockSize)
+	padded = append(padded, random.Bytes(p-1)...)
+	padded = append(padded, byte(p))
+
+	return
+}
+
+// Unpad removes ISO 10126 padding. Only the length byte can be checked.
+func (Iso10126Padder) Unpad(padded []byte) (unpadded []byte, err error) {
+	if len(padded) == 0 {
+		return make([]byte, 0), ErrEmpty
+	}
+
+	p := int(padded[len(padded)-1])
+	if p == 0 || p > len(padded) {
+		return make([]byte, 0), InvalidPaddingError{"ISO 10126"}
+	}
+
+	return padded[:len(padded)-p], nil
+}
+
+// Iso7816Padder pads with a single 0x80 byte followed by as many zero bytes
+// as needed. This is also padding method 2 of ISO/IEC 9797-1.
+type Iso7816Padder struct{}
+
+// Pad pads the message according to ISO/IEC 7816-4.
+func (Iso7816Padder) Pad(partial []byte, blockSize int) (padded []byte, err error) {
+	padded = partial
+	if err = validateBlockSize(blockSize); err != nil {
+		return
+	}
+
+	p := blockSize - (len(padded) % blockSize)
+	padded = append(padded, 0x80)
+	padded = append(padded, make([]byte, p-1)...)
+
+	return
+}
+
+// Unpad strips trailing zeros, then the 0x80 marker byte.
+func (Iso7816Padder) Unpad(padded []byte) (unpadded []byte, err error) {
+	if len(padded) == 0 {
+		return make([]byte, 0), ErrEmpty
+	}
+
+	i := len(padded) - 1
+	for i >= 0 && padded[i] == 0 {
+		i--
+	}
+
+	if i < 0 || padded[i] != 0x80 {
+		return make([]byte, 0), InvalidPaddingError{"ISO/IEC 7816-4"}
+	}
+
+	return padded[:i], nil
+}
+
+// ZeroPadder pads with zero bytes, and adds nothing if the message is
+// already a multiple of the block size. Messages which end in zeros can't
+// be unpadded unambiguously, so this is only suitable for text.
+type ZeroPadder struct{}
+
+// Pad appends zero bytes up to the next block boundary.
+func (ZeroPadder) Pad(partial []byte, blockSize int) (padded []byte, err error) {
+	padded = partial
+	if err = validateBlockSize(blockSize); err != nil {
+		return
+	}
+
+	if len(padded)%blockSize != 0 {
+		padded = append(padded, make([]byte, blockSize-(len(padded)%blockSize))...)
 	}
 
 	return
 }
+
+// Unpad strips all trailing zero bytes. It only fails on empty input.
+func (ZeroPadder) Unpad(padded []byte) (unpadded []byte, err error) {
+	if len(padded) == 0 {
+		return make([]byte, 0), ErrEmpty
+	}
+
+	i := len(padded)
+	for i > 0 && padded[i-1] == 0 {
+		i--
+	}
+
+	return padded[:i], nil
+}
@@ -2,6 +2,7 @@ package padding
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -47,6 +48,10 @@ func TestPkcs7Unpad(t *testing.T) {
 		{"challenge_15_valid", args{[]byte("ICE ICE BABY\x04\x04\x04\x04")}, []byte("ICE ICE BABY"), false},
 		{"challenge_15_invalid_1", args{[]byte("ICE ICE BABY\x05\x05\x05\x05")}, []byte{}, true},
 		{"challenge_15_invalid_2", args{[]byte("ICE ICE BABY\x01\x02\x03\x04")}, []byte{}, true},
+		{"pad_byte_in_message", args{[]byte("ICE ICE BABY\x01\x01")}, []byte("ICE ICE BABY\x01"), false},
+		{"zero_pad_byte", args{[]byte("ICE ICE BABY\x00")}, []byte{}, true},
+		{"pad_longer_than_message", args{[]byte("\x05\x05\x05")}, []byte{}, true},
+		{"empty", args{[]byte{}}, []byte{}, true},
 	}
 
 	for _, tt := range tests {
@@ -62,3 +67,80 @@ func TestPkcs7Unpad(t *testing.T) {
 		})
 	}
 }
+
+func TestPadder(t *testing.T) {
+	tests := []struct {
+		name       string
+		padder     Padder
+		partial    []byte
+		blockSize  int
+		wantPadded []byte
+	}{
+		{"pkcs7", Pkcs7Padder{}, []byte("YELLOW"), 8, []byte("YELLOW\x02\x02")},
+		{"pkcs7_full_block", Pkcs7Padder{}, []byte("YELLOW S"), 8, []byte("YELLOW S\x08\x08\x08\x08\x08\x08\x08\x08")},
+		{"ansi_x923", AnsiX923Padder{}, []byte("YELLOW"), 10, []byte("YELLOW\x00\x00\x00\x04")},
+		{"ansi_x923_full_block", AnsiX923Padder{}, []byte("YELL"), 4, []byte("YELL\x00\x00\x00\x04")},
+		{"iso_10126", Iso10126Padder{}, []byte("YELLOW"), 10, nil},
+		{"iso_7816", Iso7816Padder{}, []byte("YELLOW"), 10, []byte("YELLOW\x80\x00\x00\x00")},
+		{"iso_7816_one_byte", Iso7816Padder{}, []byte("YELLOW"), 7, []byte("YELLOW\x80")},
+		{"zero", ZeroPadder{}, []byte("YELLOW"), 8, []byte("YELLOW\x00\x00")},
+		{"zero_full_block", ZeroPadder{}, []byte("YELLOW S"), 8, []byte("YELLOW S")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPadded, err := tt.padder.Pad(append([]byte{}, tt.partial...), tt.blockSize)
+			if err != nil {
+				t.Errorf("Padder.Pad() error = %v", err)
+				return
+			}
+			if len(gotPadded)%tt.blockSize != 0 {
+				t.Errorf("Padder.Pad() has length %d, want a multiple of %d", len(gotPadded), tt.blockSize)
+			}
+			// random padding can only be checked by length
+			if tt.wantPadded != nil && !reflect.DeepEqual(gotPadded, tt.wantPadded) {
+				t.Errorf("Padder.Pad() = %q, want %q", gotPadded, tt.wantPadded)
+			}
+
+			gotUnpadded, err := tt.padder.Unpad(gotPadded)
+			if err != nil {
+				t.Errorf("Padder.Unpad() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(gotUnpadded, tt.partial) {
+				t.Errorf("Padder.Unpad() = %q, want %q", gotUnpadded, tt.partial)
+			}
+		})
+	}
+}
+
+func TestPadder_Unpad_invalid(t *testing.T) {
+	secret := "SECRET PLAINTEXT"
+	tests := []struct {
+		name    string
+		padder  Padder
+		padded  []byte
+		wantErr error
+	}{
+		{"pkcs7", Pkcs7Padder{}, []byte(secret + "\x03\x02"), InvalidPaddingError{"PKCS#7"}},
+		{"ansi_x923_nonzero_filler", AnsiX923Padder{}, []byte(secret + "\x01\x00\x03"), InvalidPaddingError{"ANSI X9.23"}},
+		{"iso_10126_too_long", Iso10126Padder{}, []byte("AB\x09"), InvalidPaddingError{"ISO 10126"}},
+		{"iso_7816_no_marker", Iso7816Padder{}, []byte(secret + "\x00\x00"), InvalidPaddingError{"ISO/IEC 7816-4"}},
+		{"iso_7816_all_zero", Iso7816Padder{}, []byte{0x00, 0x00}, InvalidPaddingError{"ISO/IEC 7816-4"}},
+		{"pkcs7_empty", Pkcs7Padder{}, []byte{}, ErrEmpty},
+		{"ansi_x923_empty", AnsiX923Padder{}, []byte{}, ErrEmpty},
+		{"iso_10126_empty", Iso10126Padder{}, []byte{}, ErrEmpty},
+		{"iso_7816_empty", Iso7816Padder{}, []byte{}, ErrEmpty},
+		{"zero_empty", ZeroPadder{}, []byte{}, ErrEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.padder.Unpad(tt.padded)
+			if err != tt.wantErr {
+				t.Errorf("Padder.Unpad() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), secret) {
+				t.Errorf("Padder.Unpad() error leaks plaintext: %v", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package MT19937
 import (
 	"encoding/binary"
 	"fmt"
+	"github.com/adavidalbertson/cryptopals/oracle"
 	"github.com/adavidalbertson/cryptopals/random"
 	"github.com/adavidalbertson/cryptopals/xor"
 	"math/rand"
@@ -36,11 +37,16 @@ type CtrCipher struct {
 	key                  uint16
 }
 
+// CtrOracle encrypts plaintexts with a random prefix under a secret key.
+// Cryptopals Set 3, Challenge 24
+// https://cryptopals.com/sets/3/challenges/24
 type CtrOracle struct {
 	key    uint16
 	cipher CtrCipher
 }
 
+var _ oracle.EncryptionOracle = &CtrOracle{}
+
 //Init initializes a new Mersenne Twister PRNG with the given seed.
 // Cryptopals Set 3, Challenge 21
 // https://cryptopals.com/sets/3/challenges/21
@@ -199,8 +205,8 @@ func NewCtrOracle() CtrOracle {
 // MT19937 CTR cipher.
 // Cryptopals Set 3, Challenge 24
 // https://cryptopals.com/sets/3/challenges/24
-func (oracle *CtrOracle) Encrypt(plaintext []byte) []byte {
-	return oracle.cipher.Encrypt(random.Prefix(plaintext))
+func (oracle *CtrOracle) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	return oracle.cipher.Encrypt(random.Prefix(plaintext)), nil
 }
 
 func PasswordResetToken() uint32 {
@@ -0,0 +1,69 @@
+// Package rc4 implements the RC4 stream cipher, whose early keystream bytes
+// are biased enough to recover plaintext encrypted many times under
+// different keys.
+// Cryptopals Set 7, Challenge 56
+// https://cryptopals.com/sets/7/challenges/56
+package rc4
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// Cipher is an RC4 keystream. It implements cipher.Stream.
+type Cipher struct {
+	s    [256]byte
+	i, j byte
+}
+
+var _ cipher.Stream = &Cipher{}
+
+// NewCipher runs the RC4 key schedule on key, which must be 1 to 256 bytes.
+func NewCipher(key []byte) (c *Cipher, err error) {
+	if len(key) < 1 || len(key) > 256 {
+		return nil, fmt.Errorf("RC4 key must be 1 to 256 bytes, got %d", len(key))
+	}
+
+	c = &Cipher{}
+	for i := range c.s {
+		c.s[i] = byte(i)
+	}
+
+	var j byte
+	for i := range c.s {
+		j += c.s[i] + key[i%len(key)]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+	}
+
+	return
+}
+
+// XORKeyStream XORs each byte of src with the next byte of the keystream,
+// and writes the result to dst.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("rc4: output smaller than input")
+	}
+
+	i, j := c.i, c.j
+	for k, b := range src {
+		i++
+		j += c.s[i]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+		dst[k] = b ^ c.s[c.s[i]+c.s[j]]
+	}
+	c.i, c.j = i, j
+}
+
+// Encrypt encrypts, or decrypts, data under key.
+func Encrypt(data, key []byte) (out []byte, err error) {
+	c, err := NewCipher(key)
+	if err != nil {
+		return
+	}
+
+	out = make([]byte, len(data))
+	c.XORKeyStream(out, data)
+
+	return
+}
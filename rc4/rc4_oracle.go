@@ -0,0 +1,40 @@
+package rc4
+
+import (
+	"encoding/base64"
+
+	"github.com/adavidalbertson/cryptopals/oracle"
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+// Rc4Oracle appends a secret cookie to each request, and encrypts the
+// result under a fresh random 128-bit key. It is safe for concurrent use.
+// Cryptopals Set 7, Challenge 56
+// https://cryptopals.com/sets/7/challenges/56
+type Rc4Oracle struct {
+	cookie []byte
+}
+
+var _ oracle.EncryptionOracle = Rc4Oracle{}
+
+// NewRc4Oracle returns an Rc4Oracle with the cookie given in Challenge 56.
+func NewRc4Oracle() Rc4Oracle {
+	// the literal is valid base64, so decoding can't fail
+	cookie, _ := base64.StdEncoding.DecodeString("QkUgU1VSRSBUTyBEUklOSyBZT1VSIE9WQUxUSU5F")
+
+	return NewRc4OracleWithCookie(cookie)
+}
+
+// NewRc4OracleWithCookie is like NewRc4Oracle, but uses the given cookie.
+func NewRc4OracleWithCookie(cookie []byte) Rc4Oracle {
+	return Rc4Oracle{append([]byte{}, cookie...)}
+}
+
+// Encrypt returns RC4(fresh key, request || cookie).
+// Cryptopals Set 7, Challenge 56
+// https://cryptopals.com/sets/7/challenges/56
+func (oracle Rc4Oracle) Encrypt(request []byte) (ciphertext []byte, err error) {
+	plaintext := append(append([]byte{}, request...), oracle.cookie...)
+
+	return Encrypt(plaintext, random.Bytes(16))
+}
@@ -0,0 +1,27 @@
+package rc4
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRc4Oracle_Encrypt(t *testing.T) {
+	oracle := NewRc4OracleWithCookie([]byte("secret cookie"))
+	request := []byte("/?q=")
+
+	a, err := oracle.Encrypt(request)
+	if err != nil {
+		t.Fatalf("Rc4Oracle.Encrypt() error = %v", err)
+	}
+	b, err := oracle.Encrypt(request)
+	if err != nil {
+		t.Fatalf("Rc4Oracle.Encrypt() error = %v", err)
+	}
+
+	if len(a) != len(request)+len("secret cookie") {
+		t.Errorf("Rc4Oracle.Encrypt() length = %d, want %d", len(a), len(request)+len("secret cookie"))
+	}
+	if bytes.Equal(a, b) {
+		t.Errorf("Rc4Oracle.Encrypt() = %x twice, want a fresh key each time", a)
+	}
+}
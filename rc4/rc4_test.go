@@ -0,0 +1,75 @@
+package rc4
+
+import (
+	"crypto/rc4"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/adavidalbertson/cryptopals/random"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+func TestEncrypt(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       []byte
+		plaintext []byte
+		want      []byte
+		wantErr   bool
+	}{
+		{"key", []byte("Key"), []byte("Plaintext"), decodeHex("bbf316e8d940af0ad3"), false},
+		{"wiki", []byte("Wiki"), []byte("pedia"), decodeHex("1021bf0420"), false},
+		{"secret", []byte("Secret"), []byte("Attack at dawn"), decodeHex("45a01f645fc35b383552544b9bf5"), false},
+		// RFC 6229, 40-bit key, keystream at offset 0
+		{"rfc6229", decodeHex("0102030405"), make([]byte, 16), decodeHex("b2396305f03dc027ccc3524a0a1118a8"), false},
+		{"empty_key", []byte{}, []byte("Plaintext"), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encrypt(tt.plaintext, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Encrypt() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCipher_XORKeyStream(t *testing.T) {
+	key := random.Bytes(16)
+	plaintext := random.Bytes(1000)
+
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	// in uneven pieces, to check the state carries over
+	got := make([]byte, len(plaintext))
+	for start, n := 0, 1; start < len(plaintext); start, n = start+n, n+7 {
+		end := start + n
+		if end > len(plaintext) {
+			end = len(plaintext)
+		}
+		c.XORKeyStream(got[start:end], plaintext[start:end])
+	}
+
+	reference, _ := rc4.NewCipher(key)
+	want := make([]byte, len(plaintext))
+	reference.XORKeyStream(want, plaintext)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cipher.XORKeyStream() = %x, want %x", got, want)
+	}
+}